@@ -0,0 +1,114 @@
+package structconf
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// Handler is a named source of values in the handler chain built by [Parse].
+// The name is reported as the source of the values it provides, and is what
+// the "sources" tag and [WithPrecedence] refer to.
+type Handler struct {
+	Name   string
+	Handle stronf.HandleFunc
+}
+
+// chainEdit modifies the sources of the handler chain, see [WithHandlers].
+type chainEdit func(sources []Handler) ([]Handler, error)
+
+// WithHandlers will replace the sources of the handler chain with the
+// handlers, from lowest to highest precedence. Each handler is passed the value
+// proposed by the ones before it, and overrides it by returning a different
+// value.
+//
+// The handler chain is computed by starting with the default sources, from
+// lowest to highest precedence: "file" (when [WithSignedFile] is used), "env",
+// "flag" (when [WithFlagSet] is used), and "default", which only provides a
+// value when none was proposed and the field is the zero value. Then each of
+// [WithHandlers], [WithHandlerBefore], and [WithPrecedence] is applied to the
+// sources in the order the options were given. The sources are always followed
+// by interpolation, references, decryption, prompting, and the required check,
+// when enabled, so that they apply to the value from any source.
+func WithHandlers(handlers ...Handler) optionFunc {
+	return func(opt *option) {
+		opt.chain = append(opt.chain, func(sources []Handler) ([]Handler, error) {
+			return slices.Clone(handlers), nil
+		})
+	}
+}
+
+// WithHandlerBefore will insert the handler into the sources of the handler
+// chain just before the source named before, giving it lower precedence. A
+// source with the same name as the handler is replaced. See [WithHandlers] for
+// how the chain is computed.
+func WithHandlerBefore(before string, handler Handler) optionFunc {
+	return func(opt *option) {
+		opt.chain = append(opt.chain, func(sources []Handler) ([]Handler, error) {
+			sources = slices.DeleteFunc(slices.Clone(sources), func(source Handler) bool {
+				return source.Name == handler.Name
+			})
+
+			i := slices.IndexFunc(sources, func(source Handler) bool {
+				return source.Name == before
+			})
+			if i < 0 {
+				return nil, fmt.Errorf("structconf: cannot insert handler %q before unknown source %q", handler.Name, before)
+			}
+
+			return slices.Insert(sources, i, handler), nil
+		})
+	}
+}
+
+// WithPrecedence will reorder the sources of the handler chain by their names,
+// from lowest to highest precedence. Sources that aren't named are removed, and
+// the built-in sources are ignored when they aren't enabled. For example,
+// WithPrecedence("file", "flag", "env", "default") has environment variables
+// override flags. See [WithHandlers] for how the chain is computed.
+func WithPrecedence(names ...string) optionFunc {
+	return func(opt *option) {
+		opt.chain = append(opt.chain, func(sources []Handler) ([]Handler, error) {
+			ordered := make([]Handler, 0, len(names))
+			for _, name := range names {
+				i := slices.IndexFunc(sources, func(source Handler) bool {
+					return source.Name == name
+				})
+
+				switch {
+				case i >= 0:
+					ordered = append(ordered, sources[i])
+
+				case !slices.Contains([]string{"file", "env", "flag", "default"}, name):
+					return nil, fmt.Errorf("structconf: unknown source %q in precedence", name)
+				}
+			}
+
+			return ordered, nil
+		})
+	}
+}
+
+// buildChain applies the edits to the default sources, returning the handlers
+// for the sources in order.
+func buildChain(sources []Handler, edits []chainEdit) ([]stronf.HandleFunc, error) {
+	for _, edit := range edits {
+		var err error
+		sources, err = edit(sources)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	handlers := make([]stronf.HandleFunc, 0, len(sources))
+	for _, source := range sources {
+		if len(source.Name) == 0 || source.Handle == nil {
+			return nil, fmt.Errorf("structconf: handler must have a name and a handle func, got %q", source.Name)
+		}
+
+		handlers = append(handlers, stronf.NamedHandler(source.Name, source.Handle))
+	}
+
+	return handlers, nil
+}
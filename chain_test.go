@@ -0,0 +1,146 @@
+package structconf_test
+
+import (
+	"context"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/kevinfalting/structconf"
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+func TestWithHandlers(t *testing.T) {
+	type Config struct {
+		Name string `conf:"env:CHAIN_NAME,flag:name,default:fallback"`
+	}
+
+	fileHandler := structconf.Handler{
+		Name: "file",
+		Handle: func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+			return "from file", nil
+		},
+	}
+
+	tests := map[string]struct {
+		env     bool
+		args    []string
+		parse   func(cfg *Config, fset *flag.FlagSet, report *structconf.Report) error
+		expect  string
+		source  string
+		wantErr bool
+	}{
+		"default chain flag overrides env": {
+			env:  true,
+			args: []string{"-name", "from flag"},
+			parse: func(cfg *Config, fset *flag.FlagSet, report *structconf.Report) error {
+				return structconf.Parse(context.Background(), cfg, structconf.WithFlagSet(fset), structconf.WithReport(report))
+			},
+			expect: "from flag",
+			source: "flag",
+		},
+		"precedence env overrides flag": {
+			env:  true,
+			args: []string{"-name", "from flag"},
+			parse: func(cfg *Config, fset *flag.FlagSet, report *structconf.Report) error {
+				return structconf.Parse(context.Background(), cfg, structconf.WithFlagSet(fset), structconf.WithReport(report),
+					structconf.WithPrecedence("file", "flag", "env", "default"))
+			},
+			expect: "from env",
+			source: "env",
+		},
+		"precedence removes unnamed sources": {
+			env:  true,
+			args: []string{"-name", "from flag"},
+			parse: func(cfg *Config, fset *flag.FlagSet, report *structconf.Report) error {
+				return structconf.Parse(context.Background(), cfg, structconf.WithFlagSet(fset), structconf.WithReport(report),
+					structconf.WithPrecedence("default"))
+			},
+			expect: "fallback",
+			source: "default",
+		},
+		"precedence unknown source": {
+			parse: func(cfg *Config, fset *flag.FlagSet, report *structconf.Report) error {
+				return structconf.Parse(context.Background(), cfg, structconf.WithPrecedence("env", "vault"))
+			},
+			wantErr: true,
+		},
+		"handler before env is overridden by env": {
+			env: true,
+			parse: func(cfg *Config, fset *flag.FlagSet, report *structconf.Report) error {
+				return structconf.Parse(context.Background(), cfg, structconf.WithReport(report),
+					structconf.WithHandlerBefore("env", fileHandler))
+			},
+			expect: "from env",
+			source: "env",
+		},
+		"handler before env overrides default": {
+			parse: func(cfg *Config, fset *flag.FlagSet, report *structconf.Report) error {
+				return structconf.Parse(context.Background(), cfg, structconf.WithReport(report),
+					structconf.WithHandlerBefore("env", fileHandler))
+			},
+			expect: "from file",
+			source: "file",
+		},
+		"handler before unknown source": {
+			parse: func(cfg *Config, fset *flag.FlagSet, report *structconf.Report) error {
+				return structconf.Parse(context.Background(), cfg, structconf.WithHandlerBefore("vault", fileHandler))
+			},
+			wantErr: true,
+		},
+		"handlers replace sources": {
+			env:  true,
+			args: []string{"-name", "from flag"},
+			parse: func(cfg *Config, fset *flag.FlagSet, report *structconf.Report) error {
+				return structconf.Parse(context.Background(), cfg, structconf.WithFlagSet(fset), structconf.WithReport(report),
+					structconf.WithHandlers(fileHandler))
+			},
+			expect: "from file",
+			source: "file",
+		},
+		"handler without name": {
+			parse: func(cfg *Config, fset *flag.FlagSet, report *structconf.Report) error {
+				return structconf.Parse(context.Background(), cfg, structconf.WithHandlers(structconf.Handler{Handle: fileHandler.Handle}))
+			},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if tt.env {
+				t.Setenv("CHAIN_NAME", "from env")
+			}
+
+			fset := flag.NewFlagSet("test", flag.ContinueOnError)
+
+			// The flag handler parses os.Args when the flag set hasn't been
+			// parsed yet.
+			args := os.Args
+			os.Args = append([]string{"test"}, tt.args...)
+			t.Cleanup(func() { os.Args = args })
+
+			var cfg Config
+			var report structconf.Report
+			err := tt.parse(&cfg, fset, &report)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal("failed to Parse:", err)
+			}
+
+			if cfg.Name != tt.expect {
+				t.Errorf("expected %q, got %q", tt.expect, cfg.Name)
+			}
+
+			if fieldReport, _ := report.Field("Name"); fieldReport.Source != tt.source {
+				t.Errorf("expected source %q, got %q", tt.source, fieldReport.Source)
+			}
+		})
+	}
+}
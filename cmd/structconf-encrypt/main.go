@@ -0,0 +1,71 @@
+// Command structconf-encrypt encrypts a value read from stdin so that it can be
+// committed to a configuration file and decrypted by the
+// [confhandler.Encrypted] handler.
+//
+// Usage:
+//
+//	structconf-encrypt -genkey > key
+//	printf 'hunter2' | structconf-encrypt -key-file key
+//	printf 'hunter2' | STRUCTCONF_KEY=... structconf-encrypt
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kevinfalting/structconf/confhandler"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	fset := flag.NewFlagSet("structconf-encrypt", flag.ContinueOnError)
+	genKey := fset.Bool("genkey", false, "print a new base64 encoded AES-256 key and exit")
+	keyEnv := fset.String("key-env", "STRUCTCONF_KEY", "environment variable holding the base64 encoded key")
+	keyFile := fset.String("key-file", "", "file holding the base64 encoded key, takes precedence over -key-env")
+	if err := fset.Parse(args); err != nil {
+		return err
+	}
+
+	if *genKey {
+		key, err := confhandler.GenerateKey()
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(stdout, key)
+		return err
+	}
+
+	var key []byte
+	var err error
+	if len(*keyFile) != 0 {
+		key, err = confhandler.EncryptedKeyFromFile(*keyFile)
+	} else {
+		key, err = confhandler.EncryptedKeyFromEnv(*keyEnv)
+	}
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := io.ReadAll(stdin)
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := confhandler.Encrypt(key, bytes.TrimRight(plaintext, "\r\n"))
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(stdout, encrypted)
+	return err
+}
@@ -0,0 +1,150 @@
+package confhandler
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// Conditional is a handler which checks relationships between fields, so it
+// must be used in a second pass over the fields once they've all been set. It
+// never proposes a value. The relationships are defined in the struct tag:
+//
+//   - required_if, such as "required_if:Mode=tls", requires the field when the
+//     other field's value is the one provided.
+//   - required_with, such as "required_with:TLS.Cert", requires the field when
+//     any of the other fields, separated by a pipe, are set.
+//   - group, such as "group:auth,exclusive", puts the field in a named group.
+//     When any field in the group is also tagged with exclusive, no more than
+//     one field in the group may be set.
+//
+// Other fields are referenced by their full path, or by their name when they
+// are in the same struct. A field is set when it's not the zero value.
+type Conditional struct {
+	fields    []stronf.Field
+	byPath    map[string]stronf.Field
+	exclusive map[string]bool
+}
+
+// NewConditional returns an initialized [Conditional] for all of the fields
+// that may be referenced.
+func NewConditional(fields []stronf.Field) *Conditional {
+	conditional := Conditional{
+		fields:    fields,
+		byPath:    make(map[string]stronf.Field, len(fields)),
+		exclusive: make(map[string]bool),
+	}
+
+	for _, field := range fields {
+		conditional.byPath[field.Path()] = field
+
+		group, ok := field.LookupTag("conf", "group")
+		if !ok {
+			continue
+		}
+
+		if _, exclusive := field.LookupTag("conf", "exclusive"); exclusive {
+			conditional.exclusive[group] = true
+		}
+	}
+
+	return &conditional
+}
+
+// Handle is the [stronf.HandleFunc] implementation of the [Conditional]
+// handler.
+func (c *Conditional) Handle(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+	if err := c.requiredIf(field); err != nil {
+		return nil, err
+	}
+
+	if err := c.requiredWith(field); err != nil {
+		return nil, err
+	}
+
+	if err := c.exclusiveGroup(field); err != nil {
+		return nil, err
+	}
+
+	return proposedValue, nil
+}
+
+func (c *Conditional) requiredIf(field stronf.Field) error {
+	condition, ok := field.LookupTag("conf", "required_if")
+	if !ok || !field.IsZero() {
+		return nil
+	}
+
+	path, want, ok := strings.Cut(condition, "=")
+	if !ok {
+		return fmt.Errorf("structconf: invalid required_if %q for field %q, must be Field=value", condition, field.Path())
+	}
+
+	other, err := c.lookup(field, path)
+	if err != nil {
+		return err
+	}
+
+	if fmt.Sprintf("%v", other.Value()) != want {
+		return nil
+	}
+
+	return fmt.Errorf("structconf: field %q is required when %q is %q", field.Path(), other.Path(), want)
+}
+
+func (c *Conditional) requiredWith(field stronf.Field) error {
+	paths, ok := field.LookupTag("conf", "required_with")
+	if !ok || !field.IsZero() {
+		return nil
+	}
+
+	for _, path := range strings.Split(paths, "|") {
+		other, err := c.lookup(field, path)
+		if err != nil {
+			return err
+		}
+
+		if !other.IsZero() {
+			return fmt.Errorf("structconf: field %q is required when %q is set", field.Path(), other.Path())
+		}
+	}
+
+	return nil
+}
+
+func (c *Conditional) exclusiveGroup(field stronf.Field) error {
+	group, ok := field.LookupTag("conf", "group")
+	if !ok || !c.exclusive[group] || field.IsZero() {
+		return nil
+	}
+
+	// Only the fields before this one are checked so that each conflict is
+	// reported once.
+	for _, other := range c.fields {
+		if other.Path() == field.Path() {
+			return nil
+		}
+
+		if otherGroup, ok := other.LookupTag("conf", "group"); ok && otherGroup == group && !other.IsZero() {
+			return fmt.Errorf("structconf: field %q cannot be set with %q in exclusive group %q", field.Path(), other.Path(), group)
+		}
+	}
+
+	return nil
+}
+
+func (c *Conditional) lookup(field stronf.Field, path string) (stronf.Field, error) {
+	if other, ok := c.byPath[path]; ok {
+		return other, nil
+	}
+
+	if i := strings.LastIndex(field.Path(), "."); i >= 0 {
+		if other, ok := c.byPath[field.Path()[:i+1]+path]; ok {
+			return other, nil
+		}
+	}
+
+	return stronf.Field{}, fmt.Errorf("structconf: field %q references unknown field %q", field.Path(), path)
+}
@@ -0,0 +1,114 @@
+package confhandler_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kevinfalting/structconf/confhandler"
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+func TestConditional(t *testing.T) {
+	type TLS struct {
+		Mode string
+		Cert string `conf:"required_if:Mode=tls"`
+		Key  string `conf:"required_with:Cert"`
+	}
+
+	type Auth struct {
+		Password string `conf:"group:auth,exclusive"`
+		Token    string `conf:"group:auth"`
+		CertFile string `conf:"group:auth,required_if:TLS.Mode=mtls"`
+	}
+
+	type A struct {
+		TLS  TLS
+		Auth Auth
+	}
+
+	testCases := map[string]struct {
+		input        A
+		expectErrors []string
+	}{
+		"nothing set": {},
+		"required_if condition met and set": {
+			input: A{
+				TLS: TLS{Mode: "tls", Cert: "cert.pem", Key: "key.pem"},
+			},
+		},
+		"required_if condition met and not set": {
+			input: A{
+				TLS: TLS{Mode: "tls"},
+			},
+			expectErrors: []string{`field "TLS.Cert" is required when "TLS.Mode" is "tls"`},
+		},
+		"required_if by full path": {
+			input: A{
+				TLS: TLS{Mode: "mtls"},
+			},
+			expectErrors: []string{`field "Auth.CertFile" is required when "TLS.Mode" is "mtls"`},
+		},
+		"required_with": {
+			input: A{
+				TLS: TLS{Cert: "cert.pem"},
+			},
+			expectErrors: []string{`field "TLS.Key" is required when "TLS.Cert" is set`},
+		},
+		"exclusive group with one set": {
+			input: A{
+				Auth: Auth{Token: "token"},
+			},
+		},
+		"exclusive group with two set": {
+			input: A{
+				Auth: Auth{Password: "password", Token: "token"},
+			},
+			expectErrors: []string{`field "Auth.Token" cannot be set with "Auth.Password" in exclusive group "auth"`},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fields, err := stronf.SettableFields(&test.input)
+			if err != nil {
+				t.Fatal("failed to SettableFields:", err)
+			}
+
+			conditional := confhandler.NewConditional(fields)
+
+			var errs []string
+			for _, field := range fields {
+				if err := field.Parse(context.Background(), conditional.Handle); err != nil {
+					errs = append(errs, err.Error())
+				}
+			}
+
+			if len(errs) != len(test.expectErrors) {
+				t.Fatalf("expected errors %q, got %q", test.expectErrors, errs)
+			}
+
+			for i, expect := range test.expectErrors {
+				if !strings.Contains(errs[i], expect) {
+					t.Errorf("expected error %q to contain %q", errs[i], expect)
+				}
+			}
+		})
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		type B struct {
+			Key string `conf:"required_with:Nope"`
+		}
+
+		var b B
+		fields, err := stronf.SettableFields(&b)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		if err := fields[0].Parse(context.Background(), confhandler.NewConditional(fields).Handle); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
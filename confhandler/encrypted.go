@@ -0,0 +1,148 @@
+package confhandler
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// EncryptedPrefix marks a value as encrypted by [Encrypt].
+const EncryptedPrefix = "enc:v1:"
+
+// Encrypted is a handler which will decrypt a proposed value that starts with
+// [EncryptedPrefix] using AES-GCM. It should be placed after the handlers that
+// source values. Errors name the field, but never contain the ciphertext or
+// plaintext. It must be created with [NewEncrypted], the zero value has no key
+// and returns an error for every encrypted value.
+type Encrypted struct {
+	aead cipher.AEAD
+}
+
+// NewEncrypted returns an initialized [Encrypted] which decrypts with the key.
+// The key must be 16, 24, or 32 bytes to select AES-128, AES-192, or AES-256.
+func NewEncrypted(key []byte) (*Encrypted, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted := Encrypted{
+		aead: aead,
+	}
+
+	return &encrypted, nil
+}
+
+// EncryptedKeyFromEnv returns the base64 encoded key in the environment
+// variable.
+func EncryptedKeyFromEnv(name string) ([]byte, error) {
+	encoded, ok := os.LookupEnv(name)
+	if !ok {
+		return nil, fmt.Errorf("structconf: encryption key environment variable %q is not set", name)
+	}
+
+	key, err := decodeKey(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("structconf: invalid encryption key in environment variable %q: %w", name, err)
+	}
+
+	return key, nil
+}
+
+// EncryptedKeyFromFile returns the base64 encoded key in the file.
+func EncryptedKeyFromFile(path string) ([]byte, error) {
+	encoded, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("structconf: failed to read encryption key: %w", err)
+	}
+
+	key, err := decodeKey(string(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("structconf: invalid encryption key in file %q: %w", path, err)
+	}
+
+	return key, nil
+}
+
+// GenerateKey returns a new random 32 byte key for AES-256, base64 encoded as
+// expected by [EncryptedKeyFromEnv] and [EncryptedKeyFromFile].
+func GenerateKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Encrypt will encrypt the plaintext with the key, returning a value that the
+// [Encrypted] handler can decrypt with the same key.
+func Encrypt(key []byte, plaintext []byte) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, plaintext, nil)
+	return EncryptedPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Handle is the [stronf.HandleFunc] implementation of the [Encrypted] handler.
+func (e *Encrypted) Handle(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+	s, ok := proposedValue.(string)
+	if !ok || !strings.HasPrefix(s, EncryptedPrefix) {
+		return proposedValue, nil
+	}
+
+	if e.aead == nil {
+		return nil, fmt.Errorf("structconf: failed to decrypt field %q: no key, use NewEncrypted", field.Name())
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(s, EncryptedPrefix))
+	if err != nil {
+		return nil, fmt.Errorf("structconf: failed to decrypt field %q: malformed value", field.Name())
+	}
+
+	if len(sealed) < e.aead.NonceSize() {
+		return nil, fmt.Errorf("structconf: failed to decrypt field %q: value too short", field.Name())
+	}
+
+	nonce, ciphertext := sealed[:e.aead.NonceSize()], sealed[e.aead.NonceSize():]
+	plaintext, err := e.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("structconf: failed to decrypt field %q: %w", field.Name(), err)
+	}
+
+	return string(plaintext), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("structconf: invalid encryption key: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, errors.New("key must be base64 encoded")
+	}
+
+	return key, nil
+}
@@ -0,0 +1,182 @@
+package confhandler_test
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kevinfalting/structconf/confhandler"
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+func TestEncrypted(t *testing.T) {
+	encodedKey, err := confhandler.GenerateKey()
+	if err != nil {
+		t.Fatal("failed to GenerateKey:", err)
+	}
+
+	t.Setenv("ENCRYPTED_KEY", encodedKey)
+	key, err := confhandler.EncryptedKeyFromEnv("ENCRYPTED_KEY")
+	if err != nil {
+		t.Fatal("failed to EncryptedKeyFromEnv:", err)
+	}
+
+	keyFile := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyFile, []byte(encodedKey+"\n"), 0o600); err != nil {
+		t.Fatal("failed to WriteFile:", err)
+	}
+
+	fileKey, err := confhandler.EncryptedKeyFromFile(keyFile)
+	if err != nil {
+		t.Fatal("failed to EncryptedKeyFromFile:", err)
+	}
+
+	if string(key) != string(fileKey) {
+		t.Fatal("expected keys from env and file to match")
+	}
+
+	encryptedHandler, err := confhandler.NewEncrypted(key)
+	if err != nil {
+		t.Fatal("failed to NewEncrypted:", err)
+	}
+
+	encrypted, err := confhandler.Encrypt(key, []byte("hunter2"))
+	if err != nil {
+		t.Fatal("failed to Encrypt:", err)
+	}
+
+	if !strings.HasPrefix(encrypted, confhandler.EncryptedPrefix) {
+		t.Fatalf("expected %q to have prefix %q", encrypted, confhandler.EncryptedPrefix)
+	}
+
+	otherKey := make([]byte, 32)
+	wrongKey, err := confhandler.Encrypt(otherKey, []byte("hunter2"))
+	if err != nil {
+		t.Fatal("failed to Encrypt:", err)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encrypted, confhandler.EncryptedPrefix))
+	if err != nil {
+		t.Fatal("failed to decode:", err)
+	}
+	sealed[len(sealed)-1] ^= 0xff
+	tampered := confhandler.EncryptedPrefix + base64.StdEncoding.EncodeToString(sealed)
+
+	type A struct {
+		String string
+	}
+
+	testCases := map[string]struct {
+		proposedValue any
+		expect        A
+		expectErr     bool
+	}{
+		"no proposedValue": {},
+		"plain value should be left alone": {
+			proposedValue: "plain",
+			expect:        A{String: "plain"},
+		},
+		"encrypted value should be decrypted": {
+			proposedValue: encrypted,
+			expect:        A{String: "hunter2"},
+		},
+		"wrong key": {
+			proposedValue: wrongKey,
+			expectErr:     true,
+		},
+		"tampered": {
+			proposedValue: tampered,
+			expectErr:     true,
+		},
+		"malformed": {
+			proposedValue: confhandler.EncryptedPrefix + "not base64!",
+			expectErr:     true,
+		},
+		"too short": {
+			proposedValue: confhandler.EncryptedPrefix + "AAAA",
+			expectErr:     true,
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var a A
+			fields, err := stronf.SettableFields(&a)
+			if err != nil {
+				t.Fatal("failed to SettableFields:", err)
+			}
+
+			handler := stronf.CombineHandlers(
+				func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+					return test.proposedValue, nil
+				},
+				encryptedHandler.Handle,
+			)
+
+			err = fields[0].Parse(context.Background(), handler)
+			if test.expectErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+
+				if !strings.Contains(err.Error(), `"String"`) {
+					t.Errorf("expected error to name the field, got %q", err)
+				}
+
+				value := strings.TrimPrefix(test.proposedValue.(string), confhandler.EncryptedPrefix)
+				if strings.Contains(err.Error(), value) || strings.Contains(err.Error(), "hunter2") {
+					t.Errorf("expected error to not contain the value, got %q", err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal("expected no error, got:", err)
+			}
+
+			if a != test.expect {
+				t.Errorf("\nexpected:\n%+v\ngot:\n%+v", test.expect, a)
+			}
+		})
+	}
+
+	t.Run("zero value", func(t *testing.T) {
+		var a A
+		fields, err := stronf.SettableFields(&a)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		encrypted, err := confhandler.Encrypt(key, []byte("hunter2"))
+		if err != nil {
+			t.Fatal("failed to Encrypt:", err)
+		}
+
+		var encryptedHandler confhandler.Encrypted
+		handler := stronf.CombineHandlers(
+			func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+				return encrypted, nil
+			},
+			encryptedHandler.Handle,
+		)
+
+		if err := fields[0].Parse(context.Background(), handler); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		if _, err := confhandler.NewEncrypted([]byte("short")); err == nil {
+			t.Error("expected error, got nil")
+		}
+
+		t.Setenv("ENCRYPTED_KEY", "not base64!")
+		if _, err := confhandler.EncryptedKeyFromEnv("ENCRYPTED_KEY"); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
@@ -2,6 +2,7 @@ package confhandler
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/kevinfalting/structconf/stronf"
@@ -9,7 +10,11 @@ import (
 
 // EnvironmentVariable is a handler which will lookup in the environment for the
 // 'env' key provided in the struct tag.
-type EnvironmentVariable struct{}
+type EnvironmentVariable struct {
+	// Unset will have [EnvironmentVariable.Unsetenv] unset the variable of every
+	// field, not only those with the 'unsetenv' key in the struct tag.
+	Unset bool
+}
 
 // Handle is the [stronf.HandleFunc] implementation of the [EnvironmentVariable] handler.
 func (ev EnvironmentVariable) Handle(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
@@ -25,3 +30,29 @@ func (ev EnvironmentVariable) Handle(ctx context.Context, field stronf.Field, pr
 
 	return val, nil
 }
+
+// Unsetenv will unset the field's environment variable if the field has the
+// 'unsetenv' key provided in the struct tag, or the handler is set to Unset
+// every variable. It should be called once the field's value has been set, so
+// the variable is no longer visible to child processes. It returns the name of
+// the variable that was unset, or an empty string if none was.
+func (ev EnvironmentVariable) Unsetenv(field stronf.Field) (string, error) {
+	environmentVariable, ok := field.LookupTag("conf", "env")
+	if !ok {
+		return "", nil
+	}
+
+	if _, unset := field.LookupTag("conf", "unsetenv"); !unset && !ev.Unset {
+		return "", nil
+	}
+
+	if _, ok := os.LookupEnv(environmentVariable); !ok {
+		return "", nil
+	}
+
+	if err := os.Unsetenv(environmentVariable); err != nil {
+		return "", fmt.Errorf("structconf: failed to unset environment variable %q for field %q: %w", environmentVariable, field.Name(), err)
+	}
+
+	return environmentVariable, nil
+}
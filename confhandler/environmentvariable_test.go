@@ -2,6 +2,7 @@ package confhandler_test
 
 import (
 	"context"
+	"os"
 	"reflect"
 	"testing"
 
@@ -101,3 +102,77 @@ func TestEnvironmentVariable(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironmentVariable_Unsetenv(t *testing.T) {
+	type A struct {
+		Unset   string `conf:"env:UNSET_ME,unsetenv"`
+		Keep    string `conf:"env:KEEP_ME"`
+		Missing string `conf:"env:MISSING_ME,unsetenv"`
+		NoTag   string
+	}
+
+	testCases := map[string]struct {
+		handler     confhandler.EnvironmentVariable
+		expectUnset []string
+		expectKept  []string
+	}{
+		"only tagged fields": {
+			handler:     confhandler.EnvironmentVariable{},
+			expectUnset: []string{"UNSET_ME"},
+			expectKept:  []string{"KEEP_ME"},
+		},
+		"every field": {
+			handler:     confhandler.EnvironmentVariable{Unset: true},
+			expectUnset: []string{"UNSET_ME", "KEEP_ME"},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("UNSET_ME", "secret")
+			t.Setenv("KEEP_ME", "not secret")
+
+			var a A
+			fields, err := stronf.SettableFields(&a)
+			if err != nil {
+				t.Fatal("failed to SettableFields:", err)
+			}
+
+			var unset []string
+			for _, field := range fields {
+				if err := field.Parse(context.Background(), test.handler.Handle); err != nil {
+					t.Fatal("expected no error, got:", err)
+				}
+
+				name, err := test.handler.Unsetenv(field)
+				if err != nil {
+					t.Fatal("expected no error, got:", err)
+				}
+
+				if len(name) != 0 {
+					unset = append(unset, name)
+				}
+			}
+
+			if !reflect.DeepEqual(test.expectUnset, unset) {
+				t.Errorf("expected %v to be unset, got %v", test.expectUnset, unset)
+			}
+
+			for _, name := range test.expectUnset {
+				if _, ok := os.LookupEnv(name); ok {
+					t.Errorf("expected %q to be unset", name)
+				}
+			}
+
+			for _, name := range test.expectKept {
+				if _, ok := os.LookupEnv(name); !ok {
+					t.Errorf("expected %q to be kept", name)
+				}
+			}
+
+			if a.Unset != "secret" || a.Keep != "not secret" {
+				t.Errorf("expected values to be set before being unset, got %+v", a)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package confhandler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// Exec is a handler which will run a command and use its stdout as the value
+// for the field. The command is looked up in the Commands mapping by the
+// field's full path first, then in the 'exec' key provided in the struct tag,
+// where the arguments are separated by whitespace. Since the struct tag is
+// split on commas, a command in the tag can't contain one, use Commands
+// instead. Only commands named in Allow may be run, so an empty Allow list will
+// refuse to run anything.
+type Exec struct {
+	// Allow is the list of programs that are permitted to be run. A program is
+	// matched against the first argument of the command exactly as written.
+	Allow []string
+
+	// Commands maps the full path of a field, such as "DB.Password", to the
+	// command, and its arguments, to run for that field. It takes precedence
+	// over the 'exec' struct tag.
+	Commands map[string][]string
+
+	// Timeout, when greater than zero, bounds how long a single command may run
+	// for on top of any deadline already set on the context.
+	Timeout time.Duration
+}
+
+// Handle is the [stronf.HandleFunc] implementation of the [Exec] handler.
+func (h Exec) Handle(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+	command, ok := h.command(field)
+	if !ok {
+		return proposedValue, nil
+	}
+
+	if len(command) == 0 {
+		return nil, fmt.Errorf("structconf: empty exec command for field %q", field.Name())
+	}
+
+	if !slices.Contains(h.Allow, command[0]) {
+		return nil, fmt.Errorf("structconf: exec command %q is not allowed for field %q", command[0], field.Name())
+	}
+
+	if h.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.Timeout)
+		defer cancel()
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// Children of the command may hold onto stdout and stderr after the command
+	// is killed, don't wait on them forever.
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = errors.Join(err, ctxErr)
+		}
+
+		msg := strings.TrimSpace(stderr.String())
+		if len(msg) == 0 {
+			return nil, fmt.Errorf("structconf: exec command %q failed for field %q: %w", command[0], field.Name(), err)
+		}
+
+		return nil, fmt.Errorf("structconf: exec command %q failed for field %q: %w: %s", command[0], field.Name(), err, msg)
+	}
+
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+func (h Exec) command(field stronf.Field) ([]string, bool) {
+	if command, ok := h.Commands[field.Path()]; ok {
+		return command, true
+	}
+
+	command, ok := field.LookupTag("conf", "exec")
+	if !ok {
+		return nil, false
+	}
+
+	return strings.Fields(command), true
+}
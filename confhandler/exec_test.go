@@ -0,0 +1,207 @@
+package confhandler_test
+
+import (
+	"context"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kevinfalting/structconf/confhandler"
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+func TestExec(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is required for this test:", err)
+	}
+
+	type A struct {
+		Tag     string `conf:"exec:echo hello"`
+		Mapping string
+		NoTag   string
+	}
+
+	testCases := map[string]struct {
+		handler       confhandler.Exec
+		input         A
+		proposedValue any
+		expect        A
+		wantErr       bool
+	}{
+		"allowed tag command should run and leave proposedValue for fields without commands": {
+			handler: confhandler.Exec{
+				Allow: []string{"echo"},
+			},
+			proposedValue: "proposed",
+			expect: A{
+				Tag:     "hello",
+				Mapping: "proposed",
+				NoTag:   "proposed",
+			},
+		},
+		"nothing allowed should refuse the tag command and leave proposedValue for fields without commands": {
+			handler:       confhandler.Exec{},
+			proposedValue: "proposed",
+			expect: A{
+				Mapping: "proposed",
+				NoTag:   "proposed",
+			},
+			wantErr: true,
+		},
+		"mapping should be used": {
+			handler: confhandler.Exec{
+				Allow: []string{"echo", "sh"},
+				Commands: map[string][]string{
+					"Mapping": {"sh", "-c", "printf 'from, mapping\n'"},
+				},
+			},
+			expect: A{
+				Tag:     "hello",
+				Mapping: "from, mapping",
+			},
+		},
+		"mapping should take precedence over tag": {
+			handler: confhandler.Exec{
+				Allow: []string{"sh"},
+				Commands: map[string][]string{
+					"Tag": {"sh", "-c", "echo mapped"},
+				},
+			},
+			expect: A{
+				Tag: "mapped",
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fields, err := stronf.SettableFields(&test.input)
+			if err != nil {
+				t.Fatal("failed to SettableFields:", err)
+			}
+
+			proposedValueExecHandler := func(testProposedValue any) stronf.HandleFunc {
+				return func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+					return test.handler.Handle(ctx, field, testProposedValue)
+				}
+			}(test.proposedValue)
+
+			var gotErr bool
+			for _, field := range fields {
+				if err := field.Parse(context.Background(), proposedValueExecHandler); err != nil {
+					if !test.wantErr {
+						t.Error("expected no error, got:", err)
+					}
+					gotErr = true
+				}
+			}
+
+			if test.wantErr && !gotErr {
+				t.Error("expected error, got nil")
+			}
+
+			if !reflect.DeepEqual(test.expect, test.input) {
+				t.Errorf("\nexpected:\n%+v\ngot:\n%+v", test.expect, test.input)
+			}
+		})
+	}
+
+	t.Run("command not in allow list", func(t *testing.T) {
+		var a A
+		fields, err := stronf.SettableFields(&a)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		err = fields[0].Parse(context.Background(), confhandler.Exec{}.Handle)
+		if err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("mapping is keyed by path", func(t *testing.T) {
+		type Creds struct {
+			Password string
+		}
+
+		type B struct {
+			DB    Creds
+			Cache Creds
+		}
+
+		var b B
+		fields, err := stronf.SettableFields(&b)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		handler := confhandler.Exec{
+			Allow: []string{"echo"},
+			Commands: map[string][]string{
+				"DB.Password": {"echo", "hunter2"},
+			},
+		}
+
+		for _, field := range fields {
+			if err := field.Parse(context.Background(), handler.Handle); err != nil {
+				t.Fatal("expected no error, got:", err)
+			}
+		}
+
+		if b != (B{DB: Creds{Password: "hunter2"}}) {
+			t.Errorf("unexpected struct %+v", b)
+		}
+	})
+
+	t.Run("stderr is included in the error", func(t *testing.T) {
+		var a A
+		fields, err := stronf.SettableFields(&a)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		handler := confhandler.Exec{
+			Allow: []string{"sh"},
+			Commands: map[string][]string{
+				"Mapping": {"sh", "-c", "echo something went wrong >&2; exit 1"},
+			},
+		}
+
+		err = fields[1].Parse(context.Background(), handler.Handle)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if !strings.Contains(err.Error(), "something went wrong") {
+			t.Errorf("expected error to contain stderr, got %q", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		var a A
+		fields, err := stronf.SettableFields(&a)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		handler := confhandler.Exec{
+			Allow: []string{"sh"},
+			Commands: map[string][]string{
+				"Mapping": {"sh", "-c", "sleep 5"},
+			},
+			Timeout: 50 * time.Millisecond,
+		}
+
+		start := time.Now()
+		err = fields[1].Parse(context.Background(), handler.Handle)
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if time.Since(start) > 3*time.Second {
+			t.Errorf("expected command to be stopped by the timeout, took %s", time.Since(start))
+		}
+	})
+}
@@ -14,6 +14,11 @@ import (
 // to interact with it.
 type Flag struct {
 	fset *flag.FlagSet
+
+	// Namespace is prepended to the name of every flag with a dot, such as
+	// "db.host" for the namespace "db", so that several structs can define
+	// their flags on the same [flag.FlagSet].
+	Namespace string
 }
 
 // NewFlag returns an initialized [Flag] with the provided [flag.FlagSet]. If no
@@ -38,7 +43,7 @@ func (f *Flag) Handle(ctx context.Context, field stronf.Field, proposedValue any
 		}
 	}
 
-	flagName, ok := field.LookupTag("conf", "flag")
+	flagName, ok := f.FlagName(field)
 	if !ok {
 		return proposedValue, nil
 	}
@@ -53,6 +58,10 @@ func (f *Flag) Handle(ctx context.Context, field stronf.Field, proposedValue any
 		return proposedValue, nil
 	}
 
+	if fVal.err != nil {
+		return nil, fVal.err
+	}
+
 	if fVal.val != nil {
 		return fVal.val, nil
 	}
@@ -61,7 +70,7 @@ func (f *Flag) Handle(ctx context.Context, field stronf.Field, proposedValue any
 		return proposedValue, nil
 	}
 
-	return stdFlag.Value.String(), nil
+	return fVal.defaultVal, nil
 }
 
 // Parse passes the args to the underlying [flag.FlagSet]'s Parse method.
@@ -72,9 +81,12 @@ func (f *Flag) Parse(args []string) error {
 // DefineFlags will define any flags on the [Flag]'s underlying [flag.FlagSet]
 // based on the [stronf.Field]'s that are passed in. It looks for the "flag" tag
 // first for the name, the looks up the "default" tag for the default value. If
-// no "default" flag is provided, the [stronf.Field]'s value is used.
-// Optionally, a "usage" flag can be provided to provide custom usage
-// information.
+// no "default" flag is provided, the [stronf.Field]'s value is used, unless the
+// field is a secret. Optionally, a "usage" flag can be provided to provide
+// custom usage information. A field which doesn't allow the "flag" source, see
+// [stronf.Field.AllowsSource], will return an error. Flags that are already
+// defined for a field at the same path and of the same type are left alone, so
+// that a struct may be parsed again.
 func (f *Flag) DefineFlags(fields []stronf.Field) error {
 	for _, field := range fields {
 		if err := f.defineFlag(field); err != nil {
@@ -85,14 +97,44 @@ func (f *Flag) DefineFlags(fields []stronf.Field) error {
 	return nil
 }
 
-func (f *Flag) defineFlag(field stronf.Field) error {
+// FlagName returns the name of the field's flag, including the namespace, and
+// reports whether the field has one.
+func (f *Flag) FlagName(field stronf.Field) (string, bool) {
 	flagName, ok := field.LookupTag("conf", "flag")
+	if !ok {
+		return "", false
+	}
+
+	if len(f.Namespace) != 0 {
+		flagName = f.Namespace + "." + flagName
+	}
+
+	return flagName, true
+}
+
+func (f *Flag) defineFlag(field stronf.Field) error {
+	flagName, ok := f.FlagName(field)
 	if !ok {
 		return nil
 	}
 
+	if !field.AllowsSource("flag") {
+		return fmt.Errorf("structconf: field %q does not allow flags, refusing to define flag %q", field.Name(), flagName)
+	}
+
+	if existing := f.fset.Lookup(flagName); existing != nil {
+		// A struct of the same type may be parsed more than once, such as when
+		// reloading, in which case the flag is already defined for the field.
+		if fVal, ok := existing.Value.(*flagVal); ok && fVal.field.Path() == field.Path() && fVal.field.Type() == field.Type() {
+			return nil
+		}
+
+		return fmt.Errorf("structconf: flag %q for field %q is already defined", flagName, field.Name())
+	}
+
+	// A secret's value prints as redacted, which must not become its default.
 	defaultVal, ok := field.LookupTag("conf", "default")
-	if (!ok && field.Value() != nil) || !field.IsZero() {
+	if !field.IsSecret() && ((!ok && field.Value() != nil) || !field.IsZero()) {
 		defaultVal = fmt.Sprintf("%v", field.Value())
 	}
 
@@ -120,20 +162,32 @@ type flagVal struct {
 	field      stronf.Field
 	val        any
 	defaultVal string
+	err        error
 }
 
 func (f *flagVal) Set(s string) error {
 	val, err := stronf.Coerce(f.field, s)
 	if err != nil {
+		// The flag package includes the value in its error message, so the error
+		// for a secret is held onto and returned by the handler instead.
+		if f.field.IsSecret() {
+			f.val, f.err = nil, err
+			return nil
+		}
+
 		return err
 	}
 
-	f.val = val
+	f.val, f.err = val, nil
 
 	return nil
 }
 
 func (f *flagVal) String() string {
+	if f.field.IsSecret() && (f.val != nil || len(f.defaultVal) != 0) {
+		return stronf.Redacted
+	}
+
 	if f.val == nil {
 		return f.defaultVal
 	}
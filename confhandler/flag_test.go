@@ -1,8 +1,11 @@
 package confhandler_test
 
 import (
+	"bytes"
 	"context"
+	"flag"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/kevinfalting/structconf/confhandler"
@@ -110,3 +113,183 @@ func TestFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestFlags_secret(t *testing.T) {
+	type Config struct {
+		Port int `conf:"flag:port,default:5432,secret"`
+	}
+
+	var cfg Config
+	fields, err := stronf.SettableFields(&cfg)
+	if err != nil {
+		t.Fatalf("failed to SettableFields: %v", err)
+	}
+
+	var out bytes.Buffer
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	fset.SetOutput(&out)
+
+	flagsHandler := confhandler.NewFlag(fset)
+	if err := flagsHandler.DefineFlags(fields); err != nil {
+		t.Fatal("failed to DefineFlags:", err)
+	}
+
+	fset.PrintDefaults()
+	if strings.Contains(out.String(), "5432") {
+		t.Errorf("expected default to be redacted from usage, got %q", out.String())
+	}
+
+	if err := flagsHandler.Parse([]string{"-port=hunter2"}); err != nil {
+		t.Fatal("expected the error to be returned by the handler, got:", err)
+	}
+
+	err = fields[0].Parse(context.Background(), flagsHandler.Handle)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if strings.Contains(err.Error(), "hunter2") || strings.Contains(out.String(), "hunter2") {
+		t.Errorf("expected value to be redacted, got %q and %q", err, out.String())
+	}
+}
+
+func TestFlags_sources(t *testing.T) {
+	type Config struct {
+		Token string `conf:"flag:token,sources:env"`
+	}
+
+	var cfg Config
+	fields, err := stronf.SettableFields(&cfg)
+	if err != nil {
+		t.Fatalf("failed to SettableFields: %v", err)
+	}
+
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+	if err := confhandler.NewFlag(fset).DefineFlags(fields); err == nil {
+		t.Error("expected error, got nil")
+	}
+
+	if fset.Lookup("token") != nil {
+		t.Error("expected token flag to not be defined")
+	}
+}
+
+func TestFlags_namespace(t *testing.T) {
+	type Config struct {
+		Host string `conf:"flag:host"`
+	}
+
+	var db, cache Config
+	dbFields, err := stronf.SettableFields(&db)
+	if err != nil {
+		t.Fatalf("failed to SettableFields: %v", err)
+	}
+
+	cacheFields, err := stronf.SettableFields(&cache)
+	if err != nil {
+		t.Fatalf("failed to SettableFields: %v", err)
+	}
+
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+
+	dbHandler := confhandler.NewFlag(fset)
+	dbHandler.Namespace = "db"
+	if err := dbHandler.DefineFlags(dbFields); err != nil {
+		t.Fatal("failed to DefineFlags:", err)
+	}
+
+	cacheHandler := confhandler.NewFlag(fset)
+	cacheHandler.Namespace = "cache"
+	if err := cacheHandler.DefineFlags(cacheFields); err != nil {
+		t.Fatal("failed to DefineFlags:", err)
+	}
+
+	if err := fset.Parse([]string{"-db.host=db.internal", "-cache.host=cache.internal"}); err != nil {
+		t.Fatal("failed to Parse:", err)
+	}
+
+	if err := dbFields[0].Parse(context.Background(), dbHandler.Handle); err != nil {
+		t.Fatal("failed to Parse field:", err)
+	}
+
+	if err := cacheFields[0].Parse(context.Background(), cacheHandler.Handle); err != nil {
+		t.Fatal("failed to Parse field:", err)
+	}
+
+	if db.Host != "db.internal" || cache.Host != "cache.internal" {
+		t.Errorf("expected db.internal and cache.internal, got %q and %q", db.Host, cache.Host)
+	}
+
+	if err := dbHandler.DefineFlags(dbFields); err != nil {
+		t.Error("expected an existing flag for the same field to be reused, got:", err)
+	}
+
+	type Other struct {
+		Host int `conf:"flag:db.host"`
+	}
+
+	otherFields, err := stronf.SettableFields(&Other{})
+	if err != nil {
+		t.Fatalf("failed to SettableFields: %v", err)
+	}
+
+	if err := confhandler.NewFlag(fset).DefineFlags(otherFields); err == nil {
+		t.Error("expected error for a flag defined by another field, got nil")
+	}
+}
+
+func TestFlags_secretType(t *testing.T) {
+	type Config struct {
+		Token stronf.Secret `conf:"flag:token"`
+	}
+
+	t.Run("not set", func(t *testing.T) {
+		var cfg Config
+		fields, err := stronf.SettableFields(&cfg)
+		if err != nil {
+			t.Fatalf("failed to SettableFields: %v", err)
+		}
+
+		flagsHandler := confhandler.NewFlag(flag.NewFlagSet("test", flag.ContinueOnError))
+		if err := flagsHandler.DefineFlags(fields); err != nil {
+			t.Fatal("failed to DefineFlags:", err)
+		}
+
+		if err := flagsHandler.Parse(nil); err != nil {
+			t.Fatal("failed to Parse:", err)
+		}
+
+		if err := fields[0].Parse(context.Background(), flagsHandler.Handle); err != nil {
+			t.Fatal("failed to Parse field:", err)
+		}
+
+		if got := cfg.Token.Reveal(); got != "" {
+			t.Errorf("expected empty secret, got %q", got)
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		var cfg Config
+		fields, err := stronf.SettableFields(&cfg)
+		if err != nil {
+			t.Fatalf("failed to SettableFields: %v", err)
+		}
+
+		flagsHandler := confhandler.NewFlag(flag.NewFlagSet("test", flag.ContinueOnError))
+		if err := flagsHandler.DefineFlags(fields); err != nil {
+			t.Fatal("failed to DefineFlags:", err)
+		}
+
+		if err := flagsHandler.Parse([]string{"-token=hunter2"}); err != nil {
+			t.Fatal("failed to Parse:", err)
+		}
+
+		if err := fields[0].Parse(context.Background(), flagsHandler.Handle); err != nil {
+			t.Fatal("failed to Parse field:", err)
+		}
+
+		if got := cfg.Token.Reveal(); got != "hunter2" {
+			t.Errorf("expected %q, got %q", "hunter2", got)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package confhandler
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// Interpolate is a handler which will expand variables in a proposed string
+// value using [os.Expand] semantics, such as "${HOME}/.cache/app". It also
+// supports the shell-style "${VAR:-default}" when VAR is unset or empty,
+// "${VAR:?message}" to error when VAR is unset or empty, and "$$" to escape a
+// literal "$". Nested expansions such as "${A:-${B}}" are not supported.
+// Proposed values that are not strings are left alone.
+type Interpolate struct {
+	// LookupEnv is used to lookup the value of a variable. If nil,
+	// [os.LookupEnv] is used.
+	LookupEnv func(key string) (string, bool)
+}
+
+// Handle is the [stronf.HandleFunc] implementation of the [Interpolate]
+// handler.
+func (h Interpolate) Handle(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+	s, ok := proposedValue.(string)
+	if !ok {
+		return proposedValue, nil
+	}
+
+	expanded, err := h.expand(s)
+	if err != nil {
+		return nil, fmt.Errorf("structconf: failed to interpolate field %q: %w", field.Name(), err)
+	}
+
+	return expanded, nil
+}
+
+// Wrap returns a [stronf.HandleFunc] which will interpolate the value returned
+// by the next handler.
+func (h Interpolate) Wrap(next stronf.HandleFunc) stronf.HandleFunc {
+	return func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+		val, err := next(ctx, field, proposedValue)
+		if err != nil {
+			return nil, err
+		}
+
+		return h.Handle(ctx, field, val)
+	}
+}
+
+func (h Interpolate) expand(s string) (string, error) {
+	lookupEnv := h.LookupEnv
+	if lookupEnv == nil {
+		lookupEnv = os.LookupEnv
+	}
+
+	var err error
+	var mapping func(string) string
+	mapping = func(name string) string {
+		if name == "$" {
+			return "$"
+		}
+
+		key, word, ok := strings.Cut(name, ":")
+		if !ok || len(word) == 0 || (word[0] != '-' && word[0] != '?') {
+			val, _ := lookupEnv(name)
+			return val
+		}
+
+		op, word := word[0], word[1:]
+		if val, ok := lookupEnv(key); ok && len(val) != 0 {
+			return val
+		}
+
+		if op == '-' {
+			return os.Expand(word, mapping)
+		}
+
+		if err == nil {
+			if len(word) == 0 {
+				word = "parameter null or not set"
+			}
+			err = fmt.Errorf("variable %q: %s", key, word)
+		}
+
+		return ""
+	}
+
+	expanded := os.Expand(s, mapping)
+	if err != nil {
+		return "", err
+	}
+
+	return expanded, nil
+}
@@ -0,0 +1,137 @@
+package confhandler_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kevinfalting/structconf/confhandler"
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+func TestInterpolate(t *testing.T) {
+	env := map[string]string{
+		"HOME":  "/home/vikki",
+		"EMPTY": "",
+	}
+
+	interpolateHandler := confhandler.Interpolate{
+		LookupEnv: func(key string) (string, bool) {
+			val, ok := env[key]
+			return val, ok
+		},
+	}
+
+	type A struct {
+		String string
+	}
+
+	testCases := map[string]struct {
+		proposedValue any
+		expect        A
+		expectErr     bool
+	}{
+		"no proposedValue": {},
+		"plain string": {
+			proposedValue: "plain",
+			expect:        A{String: "plain"},
+		},
+		"braces": {
+			proposedValue: "${HOME}/.cache/app",
+			expect:        A{String: "/home/vikki/.cache/app"},
+		},
+		"no braces": {
+			proposedValue: "$HOME/.cache/app",
+			expect:        A{String: "/home/vikki/.cache/app"},
+		},
+		"unset variable expands to empty": {
+			proposedValue: "${UNSET}/app",
+			expect:        A{String: "/app"},
+		},
+		"default when unset": {
+			proposedValue: "${UNSET:-/tmp}/app",
+			expect:        A{String: "/tmp/app"},
+		},
+		"default when empty": {
+			proposedValue: "${EMPTY:-/tmp}/app",
+			expect:        A{String: "/tmp/app"},
+		},
+		"default is expanded": {
+			proposedValue: "${UNSET:-$HOME}/app",
+			expect:        A{String: "/home/vikki/app"},
+		},
+		"default ignored when set": {
+			proposedValue: "${HOME:-/tmp}/app",
+			expect:        A{String: "/home/vikki/app"},
+		},
+		"required when set": {
+			proposedValue: "${HOME:?home is required}",
+			expect:        A{String: "/home/vikki"},
+		},
+		"required when unset": {
+			proposedValue: "${UNSET:?unset is required}",
+			expectErr:     true,
+		},
+		"required when empty": {
+			proposedValue: "${EMPTY:?}",
+			expectErr:     true,
+		},
+		"escaped": {
+			proposedValue: "$$HOME costs $$5",
+			expect:        A{String: "$HOME costs $5"},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var a A
+			fields, err := stronf.SettableFields(&a)
+			if err != nil {
+				t.Fatal("failed to SettableFields:", err)
+			}
+
+			handler := interpolateHandler.Wrap(func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+				return test.proposedValue, nil
+			})
+
+			err = fields[0].Parse(context.Background(), handler)
+			if test.expectErr {
+				if err == nil {
+					t.Error("expected error, got nil")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal("expected no error, got:", err)
+			}
+
+			if a != test.expect {
+				t.Errorf("\nexpected:\n%+v\ngot:\n%+v", test.expect, a)
+			}
+		})
+	}
+
+	t.Run("non-string proposedValue should be left alone", func(t *testing.T) {
+		type B struct {
+			Int int
+		}
+
+		var b B
+		fields, err := stronf.SettableFields(&b)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		handler := interpolateHandler.Wrap(func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+			return 5, nil
+		})
+
+		if err := fields[0].Parse(context.Background(), handler); err != nil {
+			t.Fatal("expected no error, got:", err)
+		}
+
+		if b.Int != 5 {
+			t.Errorf("expected 5, got %d", b.Int)
+		}
+	})
+}
@@ -0,0 +1,120 @@
+package confhandler
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// Prompt is a handler which will ask for a value for any required field that
+// has no value yet, using the 'usage' key provided in the struct tag as the
+// prompt. Input is validated with [stronf.Coerce] and the prompt is repeated
+// until a valid value is provided, up to MaxAttempts. It should be placed just
+// before the [Required] handler.
+//
+// When reading from an [os.File] that isn't a terminal, such as when stdin is
+// redirected, or when NonInteractive is set, the handler is non-interactive and
+// will pass along the proposed value without prompting. Any other [io.Reader]
+// is read from line by line, which allows answers to be scripted.
+type Prompt struct {
+	r           *bufio.Reader
+	w           io.Writer
+	interactive bool
+
+	// MaxAttempts is the number of times to prompt for a single field before
+	// giving up. If zero, a field will be prompted for 3 times.
+	MaxAttempts int
+
+	// NonInteractive disables prompting regardless of what's read from, such
+	// as in CI or when running as a service.
+	NonInteractive bool
+}
+
+// NewPrompt returns an initialized [Prompt] which will read answers from r and
+// write prompts to w. If no [io.Reader] is provided, [os.Stdin] is used, and if
+// no [io.Writer] is provided, [os.Stderr] is used.
+func NewPrompt(r io.Reader, w io.Writer) *Prompt {
+	if r == nil {
+		r = os.Stdin
+	}
+
+	if w == nil {
+		w = os.Stderr
+	}
+
+	interactive := true
+	if f, ok := r.(*os.File); ok {
+		info, err := f.Stat()
+		interactive = err == nil && info.Mode()&os.ModeCharDevice != 0
+	}
+
+	prompt := Prompt{
+		r:           bufio.NewReader(r),
+		w:           w,
+		interactive: interactive,
+	}
+
+	return &prompt
+}
+
+// Interactive reports whether the [Prompt] will prompt for values.
+func (p *Prompt) Interactive() bool {
+	return p.interactive && !p.NonInteractive
+}
+
+// Handle is the [stronf.HandleFunc] implementation of the [Prompt] handler.
+func (p *Prompt) Handle(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+	if proposedValue != nil || !p.Interactive() {
+		return proposedValue, nil
+	}
+
+	if _, required := field.LookupTag("conf", "required"); !required || !field.IsZero() {
+		return nil, nil
+	}
+
+	label := field.Name()
+	if usage, ok := field.LookupTag("conf", "usage"); ok && len(usage) != 0 {
+		label = fmt.Sprintf("%s (%s)", usage, field.Name())
+	}
+
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if _, err := fmt.Fprintf(p.w, "%s: ", label); err != nil {
+			return nil, err
+		}
+
+		line, err := p.r.ReadString('\n')
+		if err != nil && (!errors.Is(err, io.EOF) || len(line) == 0) {
+			return nil, fmt.Errorf("structconf: failed to read value for field %q: %w", field.Name(), err)
+		}
+
+		line = strings.TrimRight(line, "\r\n")
+		if len(line) == 0 {
+			fmt.Fprintln(p.w, "a value is required")
+			continue
+		}
+
+		if _, err := stronf.Coerce(field, line); err != nil {
+			fmt.Fprintln(p.w, "invalid value:", err)
+			continue
+		}
+
+		return line, nil
+	}
+
+	return nil, fmt.Errorf("structconf: no valid value provided for field %q after %d attempts", field.Name(), maxAttempts)
+}
@@ -0,0 +1,184 @@
+package confhandler_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kevinfalting/structconf/confhandler"
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+func TestPrompt(t *testing.T) {
+	type A struct {
+		Name     string `conf:"required,usage:your name"`
+		Port     int    `conf:"required"`
+		Optional string
+	}
+
+	testCases := map[string]struct {
+		input         A
+		answers       string
+		proposedValue any
+		expect        A
+		expectPrompts []string
+	}{
+		"prompts for required fields": {
+			answers: "Vikki\n8080\n",
+			expect: A{
+				Name: "Vikki",
+				Port: 8080,
+			},
+			expectPrompts: []string{"your name (Name): ", "Port: "},
+		},
+		"re-prompts on invalid and empty values": {
+			answers: "\nVikki\nnot a number\n8080",
+			expect: A{
+				Name: "Vikki",
+				Port: 8080,
+			},
+			expectPrompts: []string{"your name (Name): ", "a value is required", "Port: ", "invalid value"},
+		},
+		"field value set should not prompt": {
+			input: A{
+				Name: "Vikki",
+				Port: 8080,
+			},
+			expect: A{
+				Name: "Vikki",
+				Port: 8080,
+			},
+		},
+		"proposedValue should not prompt": {
+			proposedValue: "22",
+			expect: A{
+				Name:     "22",
+				Port:     22,
+				Optional: "22",
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			fields, err := stronf.SettableFields(&test.input)
+			if err != nil {
+				t.Fatal("failed to SettableFields:", err)
+			}
+
+			var out bytes.Buffer
+			promptHandler := confhandler.NewPrompt(strings.NewReader(test.answers), &out)
+			proposedValuePromptHandler := func(testProposedValue any) stronf.HandleFunc {
+				return func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+					return promptHandler.Handle(ctx, field, testProposedValue)
+				}
+			}(test.proposedValue)
+
+			for _, field := range fields {
+				if err := field.Parse(context.Background(), proposedValuePromptHandler); err != nil {
+					t.Error("expected no error, got:", err)
+				}
+			}
+
+			if !reflect.DeepEqual(test.expect, test.input) {
+				t.Errorf("\nexpected:\n%+v\ngot:\n%+v", test.expect, test.input)
+			}
+
+			for _, prompt := range test.expectPrompts {
+				if !strings.Contains(out.String(), prompt) {
+					t.Errorf("expected output to contain %q, got %q", prompt, out.String())
+				}
+			}
+
+			if len(test.expectPrompts) == 0 && out.Len() != 0 {
+				t.Errorf("expected no prompts, got %q", out.String())
+			}
+		})
+	}
+
+	t.Run("gives up after MaxAttempts", func(t *testing.T) {
+		var a A
+		fields, err := stronf.SettableFields(&a)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		var out bytes.Buffer
+		promptHandler := confhandler.NewPrompt(strings.NewReader("x\nx\nx\n8080\n"), &out)
+		promptHandler.MaxAttempts = 2
+
+		if err := fields[1].Parse(context.Background(), promptHandler.Handle); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("no more input", func(t *testing.T) {
+		var a A
+		fields, err := stronf.SettableFields(&a)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		var out bytes.Buffer
+		promptHandler := confhandler.NewPrompt(strings.NewReader(""), &out)
+		if err := fields[0].Parse(context.Background(), promptHandler.Handle); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("non-interactive file should not prompt", func(t *testing.T) {
+		f, err := os.CreateTemp(t.TempDir(), "answers")
+		if err != nil {
+			t.Fatal("failed to CreateTemp:", err)
+		}
+		defer f.Close()
+
+		var a A
+		fields, err := stronf.SettableFields(&a)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		var out bytes.Buffer
+		promptHandler := confhandler.NewPrompt(f, &out)
+		if promptHandler.Interactive() {
+			t.Fatal("expected a regular file to be non-interactive")
+		}
+
+		handler := stronf.CombineHandlers(promptHandler.Handle, confhandler.Required{}.Handle)
+		if err := fields[0].Parse(context.Background(), handler); err == nil {
+			t.Error("expected required error, got nil")
+		}
+
+		if out.Len() != 0 {
+			t.Errorf("expected no prompts, got %q", out.String())
+		}
+	})
+
+	t.Run("non-interactive should not prompt", func(t *testing.T) {
+		var a A
+		fields, err := stronf.SettableFields(&a)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		var out bytes.Buffer
+		promptHandler := confhandler.NewPrompt(strings.NewReader("Vikki\n"), &out)
+		promptHandler.NonInteractive = true
+		if promptHandler.Interactive() {
+			t.Fatal("expected the prompt to be non-interactive")
+		}
+
+		handler := stronf.CombineHandlers(promptHandler.Handle, confhandler.Required{}.Handle)
+		if err := fields[0].Parse(context.Background(), handler); err == nil {
+			t.Error("expected required error, got nil")
+		}
+
+		if out.Len() != 0 {
+			t.Errorf("expected no prompts, got %q", out.String())
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package confhandler
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// ResolveFunc resolves the reference with the scheme already removed. For
+// "file:///run/secrets/db" the ref is "///run/secrets/db".
+type ResolveFunc func(ctx context.Context, ref string) (string, error)
+
+// Reference is a handler which will resolve a proposed value that refers to
+// another location, such as "file:///run/secrets/db" or "env://CI_TOKEN". It
+// should be placed after the handlers that source values. Resolved values are
+// resolved again if they are a reference themselves, and a cycle results in an
+// error. The zero value has no schemes registered, use [NewReference] for the
+// built-in ones.
+type Reference struct {
+	resolvers map[string]ResolveFunc
+}
+
+// NewReference returns an initialized [Reference] with the file, env, and
+// base64 schemes registered.
+func NewReference() *Reference {
+	ref := Reference{
+		resolvers: make(map[string]ResolveFunc),
+	}
+
+	ref.Register("file", resolveFile)
+	ref.Register("env", resolveEnv)
+	ref.Register("base64", resolveBase64)
+
+	return &ref
+}
+
+// Register will add or replace the [ResolveFunc] used for the scheme. Schemes
+// are matched case insensitively.
+func (r *Reference) Register(scheme string, resolve ResolveFunc) {
+	if r.resolvers == nil {
+		r.resolvers = make(map[string]ResolveFunc)
+	}
+
+	r.resolvers[strings.ToLower(scheme)] = resolve
+}
+
+// Handle is the [stronf.HandleFunc] implementation of the [Reference] handler.
+func (r *Reference) Handle(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+	s, ok := proposedValue.(string)
+	if !ok {
+		return proposedValue, nil
+	}
+
+	seen := make(map[string]bool)
+	for {
+		resolve, ref, ok := r.lookup(s)
+		if !ok {
+			return s, nil
+		}
+
+		if seen[s] {
+			return nil, fmt.Errorf("structconf: reference cycle detected at %q for field %q", s, field.Name())
+		}
+		seen[s] = true
+
+		resolved, err := resolve(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("structconf: failed to resolve reference for field %q: %w", field.Name(), err)
+		}
+
+		s = resolved
+	}
+}
+
+func (r *Reference) lookup(s string) (ResolveFunc, string, bool) {
+	scheme, ref, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, "", false
+	}
+
+	resolve, ok := r.resolvers[strings.ToLower(scheme)]
+	if !ok {
+		return nil, "", false
+	}
+
+	return resolve, ref, true
+}
+
+func resolveFile(ctx context.Context, ref string) (string, error) {
+	path := strings.TrimPrefix(ref, "//")
+	if len(path) == 0 {
+		return "", errors.New("empty file path")
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+func resolveEnv(ctx context.Context, ref string) (string, error) {
+	name := strings.TrimPrefix(ref, "//")
+	val, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %q is not set", name)
+	}
+
+	return val, nil
+}
+
+func resolveBase64(ctx context.Context, ref string) (string, error) {
+	b, err := base64.StdEncoding.DecodeString(ref)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
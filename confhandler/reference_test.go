@@ -0,0 +1,182 @@
+package confhandler_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kevinfalting/structconf/confhandler"
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+func TestReference(t *testing.T) {
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretFile, []byte("from file\n"), 0o600); err != nil {
+		t.Fatal("failed to WriteFile:", err)
+	}
+
+	t.Setenv("REFERENCE_TOKEN", "from env")
+	t.Setenv("REFERENCE_POINTER", "env://REFERENCE_TOKEN")
+
+	type A struct {
+		String string
+	}
+
+	testCases := map[string]struct {
+		proposedValue any
+		expect        A
+	}{
+		"no proposedValue": {},
+		"plain value should be left alone": {
+			proposedValue: "plain value",
+			expect:        A{String: "plain value"},
+		},
+		"unregistered scheme should be left alone": {
+			proposedValue: "https://example.com",
+			expect:        A{String: "https://example.com"},
+		},
+		"file": {
+			proposedValue: "file://" + secretFile,
+			expect:        A{String: "from file"},
+		},
+		"env": {
+			proposedValue: "env://REFERENCE_TOKEN",
+			expect:        A{String: "from env"},
+		},
+		"base64": {
+			proposedValue: "base64:ZnJvbSBiYXNlNjQ=",
+			expect:        A{String: "from base64"},
+		},
+		"scheme is case insensitive": {
+			proposedValue: "ENV://REFERENCE_TOKEN",
+			expect:        A{String: "from env"},
+		},
+		"nested reference should be resolved": {
+			proposedValue: "env://REFERENCE_POINTER",
+			expect:        A{String: "from env"},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var a A
+			fields, err := stronf.SettableFields(&a)
+			if err != nil {
+				t.Fatal("failed to SettableFields:", err)
+			}
+
+			refHandler := confhandler.NewReference()
+			proposedValueRefHandler := func(testProposedValue any) stronf.HandleFunc {
+				return func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+					return refHandler.Handle(ctx, field, testProposedValue)
+				}
+			}(test.proposedValue)
+
+			if err := fields[0].Parse(context.Background(), proposedValueRefHandler); err != nil {
+				t.Fatal("expected no error, got:", err)
+			}
+
+			if a != test.expect {
+				t.Errorf("\nexpected:\n%+v\ngot:\n%+v", test.expect, a)
+			}
+		})
+	}
+
+	t.Run("custom scheme", func(t *testing.T) {
+		var a A
+		fields, err := stronf.SettableFields(&a)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		refHandler := confhandler.NewReference()
+		refHandler.Register("vault", func(ctx context.Context, ref string) (string, error) {
+			return "resolved " + ref, nil
+		})
+
+		handler := stronf.CombineHandlers(
+			func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+				return "vault:secret/db", nil
+			},
+			refHandler.Handle,
+		)
+
+		if err := fields[0].Parse(context.Background(), handler); err != nil {
+			t.Fatal("expected no error, got:", err)
+		}
+
+		if a.String != "resolved secret/db" {
+			t.Errorf("expected %q, got %q", "resolved secret/db", a.String)
+		}
+	})
+
+	t.Run("zero value", func(t *testing.T) {
+		var a A
+		fields, err := stronf.SettableFields(&a)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		var refHandler confhandler.Reference
+		refHandler.Register("vault", func(ctx context.Context, ref string) (string, error) {
+			return "resolved " + ref, nil
+		})
+
+		handler := stronf.CombineHandlers(
+			func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+				return "vault:secret/db", nil
+			},
+			refHandler.Handle,
+		)
+
+		if err := fields[0].Parse(context.Background(), handler); err != nil {
+			t.Fatal("expected no error, got:", err)
+		}
+
+		if a.String != "resolved secret/db" {
+			t.Errorf("expected %q, got %q", "resolved secret/db", a.String)
+		}
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		t.Setenv("REFERENCE_A", "env://REFERENCE_B")
+		t.Setenv("REFERENCE_B", "env://REFERENCE_A")
+
+		var a A
+		fields, err := stronf.SettableFields(&a)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		handler := stronf.CombineHandlers(
+			func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+				return "env://REFERENCE_A", nil
+			},
+			confhandler.NewReference().Handle,
+		)
+
+		if err := fields[0].Parse(context.Background(), handler); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("missing env", func(t *testing.T) {
+		var a A
+		fields, err := stronf.SettableFields(&a)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		handler := stronf.CombineHandlers(
+			func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+				return "env://REFERENCE_DOES_NOT_EXIST", nil
+			},
+			confhandler.NewReference().Handle,
+		)
+
+		if err := fields[0].Parse(context.Background(), handler); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
@@ -0,0 +1,81 @@
+package confhandler
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SignatureExt is appended to the path of a file to find its detached
+// signature, such as "config.json.sig".
+const SignatureExt = ".sig"
+
+// ErrSignatureInvalid is wrapped by a [SignatureError] when the signature was
+// not made by any of the trusted keys.
+var ErrSignatureInvalid = errors.New("signature does not match any trusted key")
+
+// SignatureError is returned when a file could not be verified.
+type SignatureError struct {
+	Path string
+	Err  error
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("structconf: failed to verify signature of %q: %v", e.Path, e.Err)
+}
+
+func (e *SignatureError) Unwrap() error {
+	return e.Err
+}
+
+// VerifyFile returns the contents of the file at path only if its detached
+// signature, at path with [SignatureExt] appended, was made by one of the
+// trusted keys. The signature file contains the base64 encoded ed25519
+// signature, as written by [SignFile]. Any failure is a [*SignatureError].
+func VerifyFile(path string, trusted ...ed25519.PublicKey) ([]byte, error) {
+	if len(trusted) == 0 {
+		return nil, &SignatureError{Path: path, Err: errors.New("no trusted keys")}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &SignatureError{Path: path, Err: err}
+	}
+
+	encoded, err := os.ReadFile(path + SignatureExt)
+	if err != nil {
+		return nil, &SignatureError{Path: path, Err: err}
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return nil, &SignatureError{Path: path, Err: errors.New("malformed signature")}
+	}
+
+	for _, key := range trusted {
+		if len(key) == ed25519.PublicKeySize && ed25519.Verify(key, data, sig) {
+			return data, nil
+		}
+	}
+
+	return nil, &SignatureError{Path: path, Err: ErrSignatureInvalid}
+}
+
+// SignFile will sign the file at path with the key, writing the base64 encoded
+// signature next to it at path with [SignatureExt] appended.
+func SignFile(path string, key ed25519.PrivateKey) error {
+	if len(key) != ed25519.PrivateKeySize {
+		return errors.New("structconf: invalid ed25519 private key")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	return os.WriteFile(path+SignatureExt, []byte(sig+"\n"), 0o644)
+}
@@ -0,0 +1,87 @@
+package confhandler_test
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kevinfalting/structconf/confhandler"
+)
+
+func TestVerifyFile(t *testing.T) {
+	trustedPub, trustedPriv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal("failed to GenerateKey:", err)
+	}
+
+	otherPub, otherPriv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal("failed to GenerateKey:", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	content := []byte(`{"NAME": "signed"}`)
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatal("failed to WriteFile:", err)
+	}
+
+	t.Run("missing signature", func(t *testing.T) {
+		_, err := confhandler.VerifyFile(path, trustedPub)
+		var sigErr *confhandler.SignatureError
+		if !errors.As(err, &sigErr) {
+			t.Errorf("expected SignatureError, got %v", err)
+		}
+	})
+
+	if err := confhandler.SignFile(path, trustedPriv); err != nil {
+		t.Fatal("failed to SignFile:", err)
+	}
+
+	t.Run("trusted", func(t *testing.T) {
+		data, err := confhandler.VerifyFile(path, otherPub, trustedPub)
+		if err != nil {
+			t.Fatal("expected no error, got:", err)
+		}
+
+		if string(data) != string(content) {
+			t.Errorf("expected %q, got %q", content, data)
+		}
+	})
+
+	t.Run("untrusted", func(t *testing.T) {
+		_, err := confhandler.VerifyFile(path, otherPub)
+		if !errors.Is(err, confhandler.ErrSignatureInvalid) {
+			t.Errorf("expected %v, got %v", confhandler.ErrSignatureInvalid, err)
+		}
+	})
+
+	t.Run("no trusted keys", func(t *testing.T) {
+		if _, err := confhandler.VerifyFile(path); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		if err := os.WriteFile(path, []byte(`{"NAME": "tampered"}`), 0o600); err != nil {
+			t.Fatal("failed to WriteFile:", err)
+		}
+
+		_, err := confhandler.VerifyFile(path, trustedPub)
+		if !errors.Is(err, confhandler.ErrSignatureInvalid) {
+			t.Errorf("expected %v, got %v", confhandler.ErrSignatureInvalid, err)
+		}
+	})
+
+	t.Run("signed by other key", func(t *testing.T) {
+		if err := confhandler.SignFile(path, otherPriv); err != nil {
+			t.Fatal("failed to SignFile:", err)
+		}
+
+		_, err := confhandler.VerifyFile(path, trustedPub)
+		if !errors.Is(err, confhandler.ErrSignatureInvalid) {
+			t.Errorf("expected %v, got %v", confhandler.ErrSignatureInvalid, err)
+		}
+	})
+}
@@ -0,0 +1,208 @@
+package confhandler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// Stdin is a handler which will read a configuration document from an
+// [io.Reader] exactly once, the first time it's needed. The document may be
+// either a JSON object or KEY=VALUE lines, detected by the first non-space
+// character. A field is looked up with the 'key' key provided in the struct
+// tag, a dot separated path for nested JSON objects, then with the 'env' key.
+type Stdin struct {
+	r io.Reader
+
+	once   sync.Once
+	err    error
+	values map[string]string
+}
+
+// NewStdin returns an initialized [Stdin] which will read from the provided
+// [io.Reader]. If no [io.Reader] is provided, [os.Stdin] is used.
+func NewStdin(r io.Reader) *Stdin {
+	if r == nil {
+		r = os.Stdin
+	}
+
+	stdin := Stdin{
+		r: r,
+	}
+
+	return &stdin
+}
+
+// Handle is the [stronf.HandleFunc] implementation of the [Stdin] handler.
+func (s *Stdin) Handle(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+	var keys []string
+	for _, tag := range []string{"key", "env"} {
+		if key, ok := field.LookupTag("conf", tag); ok {
+			keys = append(keys, key)
+		}
+	}
+
+	if len(keys) == 0 {
+		return proposedValue, nil
+	}
+
+	s.once.Do(func() {
+		s.values, s.err = s.read()
+	})
+
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	for _, key := range keys {
+		if val, ok := s.values[key]; ok {
+			return val, nil
+		}
+	}
+
+	return proposedValue, nil
+}
+
+func (s *Stdin) read() (map[string]string, error) {
+	if f, ok := s.r.(*os.File); ok {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, fmt.Errorf("structconf: failed to stat %s: %w", f.Name(), err)
+		}
+
+		if info.Mode()&os.ModeCharDevice != 0 {
+			return nil, fmt.Errorf("structconf: %s is a terminal, pipe a JSON or KEY=VALUE document into it", f.Name())
+		}
+	}
+
+	data, err := io.ReadAll(s.r)
+	if err != nil {
+		return nil, fmt.Errorf("structconf: failed to read configuration: %w", err)
+	}
+
+	data = bytes.TrimSpace(data)
+	values := make(map[string]string)
+	if len(data) == 0 {
+		return values, nil
+	}
+
+	if data[0] == '{' {
+		if err := parseJSON(data, values); err != nil {
+			return nil, fmt.Errorf("structconf: failed to parse JSON configuration: %w", err)
+		}
+
+		return values, nil
+	}
+
+	if err := parseKeyValue(data, values); err != nil {
+		return nil, fmt.Errorf("structconf: failed to parse KEY=VALUE configuration: %w", err)
+	}
+
+	return values, nil
+}
+
+// parseJSON flattens the JSON object into values, joining the keys of nested
+// objects with a dot. Leaf values are stored as strings, objects and arrays
+// are stored as JSON as well so that they may be unmarshaled by the field.
+func parseJSON(data []byte, values map[string]string) error {
+	var doc map[string]any
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		return err
+	}
+
+	var flatten func(prefix string, v any) error
+	flatten = func(prefix string, v any) error {
+		switch v := v.(type) {
+		case nil:
+			return nil
+
+		case string:
+			values[prefix] = v
+
+		case json.Number:
+			values[prefix] = v.String()
+
+		case bool:
+			values[prefix] = strconv.FormatBool(v)
+
+		case map[string]any:
+			if len(prefix) != 0 {
+				b, err := json.Marshal(v)
+				if err != nil {
+					return err
+				}
+				values[prefix] = string(b)
+				prefix += "."
+			}
+
+			for key, val := range v {
+				if err := flatten(prefix+key, val); err != nil {
+					return err
+				}
+			}
+
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			values[prefix] = string(b)
+		}
+
+		return nil
+	}
+
+	return flatten("", doc)
+}
+
+// parseKeyValue parses KEY=VALUE lines into values. Blank lines and lines
+// starting with a '#' are ignored, an optional "export " prefix is allowed, and
+// values may be wrapped in single or double quotes.
+func parseKeyValue(data []byte, values map[string]string) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		key, val, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("line %d: missing '='", lineNum)
+		}
+
+		key = strings.TrimSpace(key)
+		if len(key) == 0 {
+			return fmt.Errorf("line %d: missing key", lineNum)
+		}
+
+		val = strings.TrimSpace(val)
+		if len(val) >= 2 && (val[0] == '"' || val[0] == '\'') && val[len(val)-1] == val[0] {
+			if val[0] == '"' {
+				unquoted, err := strconv.Unquote(val)
+				if err != nil {
+					return fmt.Errorf("line %d: %w", lineNum, err)
+				}
+				val = unquoted
+			} else {
+				val = val[1 : len(val)-1]
+			}
+		}
+
+		values[key] = val
+	}
+
+	return scanner.Err()
+}
@@ -0,0 +1,165 @@
+package confhandler_test
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kevinfalting/structconf/confhandler"
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+func TestStdin(t *testing.T) {
+	type Database struct {
+		Host string `conf:"key:database.host"`
+		Port int    `conf:"key:database.port"`
+	}
+
+	type A struct {
+		Name     string        `conf:"env:NAME"`
+		Debug    bool          `conf:"env:DEBUG"`
+		Timeout  time.Duration `conf:"key:timeout,env:TIMEOUT"`
+		Database Database
+		NoTag    string
+	}
+
+	testCases := map[string]struct {
+		input         string
+		proposedValue any
+		expect        A
+	}{
+		"empty input": {
+			input: "",
+		},
+		"missing keys should leave proposedValue": {
+			input:         `{"DEBUG": true, "timeout": "1s"}`,
+			proposedValue: "5",
+			expect: A{
+				Name:    "5",
+				Debug:   true,
+				Timeout: time.Second,
+				Database: Database{
+					Host: "5",
+					Port: 5,
+				},
+				NoTag: "5",
+			},
+		},
+		"json": {
+			input: `{
+				"NAME": "from json",
+				"DEBUG": true,
+				"timeout": "5s",
+				"database": {"host": "localhost", "port": 5432}
+			}`,
+			expect: A{
+				Name:    "from json",
+				Debug:   true,
+				Timeout: 5 * time.Second,
+				Database: Database{
+					Host: "localhost",
+					Port: 5432,
+				},
+			},
+		},
+		"key value": {
+			input: `
+				# a comment
+				NAME="from env"
+				export DEBUG=true
+				TIMEOUT='5s'
+				database.port = 5432
+			`,
+			expect: A{
+				Name:    "from env",
+				Debug:   true,
+				Timeout: 5 * time.Second,
+				Database: Database{
+					Port: 5432,
+				},
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var a A
+			fields, err := stronf.SettableFields(&a)
+			if err != nil {
+				t.Fatal("failed to SettableFields:", err)
+			}
+
+			stdinHandler := confhandler.NewStdin(strings.NewReader(test.input))
+			proposedValueStdinHandler := func(testProposedValue any) stronf.HandleFunc {
+				return func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+					return stdinHandler.Handle(ctx, field, testProposedValue)
+				}
+			}(test.proposedValue)
+
+			for _, field := range fields {
+				if err := field.Parse(context.Background(), proposedValueStdinHandler); err != nil {
+					t.Error("expected no error, got:", err)
+				}
+			}
+
+			if !reflect.DeepEqual(test.expect, a) {
+				t.Errorf("\nexpected:\n%+v\ngot:\n%+v", test.expect, a)
+			}
+		})
+	}
+
+	t.Run("reads once", func(t *testing.T) {
+		var a A
+		fields, err := stronf.SettableFields(&a)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		r := &countingReader{r: strings.NewReader(`{"NAME": "once", "DEBUG": true}`)}
+		stdinHandler := confhandler.NewStdin(r)
+		for _, field := range fields {
+			if err := field.Parse(context.Background(), stdinHandler.Handle); err != nil {
+				t.Error("expected no error, got:", err)
+			}
+		}
+
+		if a.Name != "once" || !a.Debug {
+			t.Errorf("unexpected config %+v", a)
+		}
+
+		if r.eofs != 1 {
+			t.Errorf("expected reader to be read to EOF once, got %d", r.eofs)
+		}
+	})
+
+	t.Run("invalid document", func(t *testing.T) {
+		var a A
+		fields, err := stronf.SettableFields(&a)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		for _, input := range []string{`{"NAME": `, "NAME"} {
+			stdinHandler := confhandler.NewStdin(strings.NewReader(input))
+			if err := fields[0].Parse(context.Background(), stdinHandler.Handle); err == nil {
+				t.Errorf("expected error for %q, got nil", input)
+			}
+		}
+	})
+}
+
+type countingReader struct {
+	r    io.Reader
+	eofs int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if err == io.EOF {
+		c.eofs++
+	}
+	return n, err
+}
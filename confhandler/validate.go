@@ -0,0 +1,228 @@
+package confhandler
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// ValidationError is returned by [Validate] for each constraint a field's value
+// violates. It never contains the value itself.
+type ValidationError struct {
+	// Path is the full path to the field from the root struct.
+	Path string
+
+	// Constraint is the violated constraint as written in the struct tag, such
+	// as "min:1".
+	Constraint string
+
+	// Reason describes why the constraint was violated.
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("structconf: field %q violates %q: %s", e.Path, e.Constraint, e.Reason)
+}
+
+// Validate will check the field's value against the constraints provided in the
+// struct tag. It should be called once the field's value has been set. The
+// supported constraints are:
+//
+//   - min and max, such as "min:1", for numbers and [time.Duration]'s.
+//   - minlen and maxlen, such as "maxlen:64", for the number of characters in
+//     a string.
+//   - oneof, such as "oneof:debug|info|warn", for values separated by a pipe.
+//   - pattern, such as "pattern:^[a-z]+$", for a regular expression that a
+//     string must match. The pattern cannot contain a comma.
+//
+// A [*ValidationError] is returned for each violated constraint, joined
+// together with [errors.Join].
+func Validate(field stronf.Field) error {
+	var errs []error
+	for _, validate := range []func(stronf.Field) error{
+		validateMin,
+		validateMax,
+		validateMinLen,
+		validateMaxLen,
+		validateOneOf,
+		validatePattern,
+	} {
+		if err := validate(field); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func validateMin(field stronf.Field) error {
+	return validateBound(field, "min", func(result int) bool { return result >= 0 }, "must be at least")
+}
+
+func validateMax(field stronf.Field) error {
+	return validateBound(field, "max", func(result int) bool { return result <= 0 }, "must be at most")
+}
+
+func validateBound(field stronf.Field, key string, ok func(result int) bool, reason string) error {
+	bound, found := field.LookupTag("conf", key)
+	if !found {
+		return nil
+	}
+
+	result, err := compareNumber(field, bound)
+	if err != nil {
+		return fmt.Errorf("structconf: invalid %s constraint for field %q: %w", key, field.Path(), err)
+	}
+
+	if ok(result) {
+		return nil
+	}
+
+	return &ValidationError{
+		Path:       field.Path(),
+		Constraint: key + ":" + bound,
+		Reason:     fmt.Sprintf("%s %s", reason, bound),
+	}
+}
+
+// compareNumber compares the field's value to the bound, returning -1 if the
+// value is less than the bound, 0 if equal, and +1 if greater.
+func compareNumber(field stronf.Field, bound string) (int, error) {
+	rVal := reflect.ValueOf(field.Value())
+
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(bound)
+		if err != nil {
+			return 0, err
+		}
+
+		return cmp.Compare(time.Duration(rVal.Int()), d), nil
+	}
+
+	switch rVal.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(bound, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(rVal.Int(), i), nil
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(bound, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(rVal.Uint(), u), nil
+
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(bound, 64)
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(rVal.Float(), f), nil
+
+	default:
+		return 0, fmt.Errorf("unsupported type %q", field.Type())
+	}
+}
+
+func validateMinLen(field stronf.Field) error {
+	return validateLen(field, "minlen", func(n, bound int) bool { return n >= bound }, "must be at least %d characters")
+}
+
+func validateMaxLen(field stronf.Field) error {
+	return validateLen(field, "maxlen", func(n, bound int) bool { return n <= bound }, "must be at most %d characters")
+}
+
+func validateLen(field stronf.Field, key string, ok func(n, bound int) bool, reason string) error {
+	val, found := field.LookupTag("conf", key)
+	if !found {
+		return nil
+	}
+
+	bound, err := strconv.Atoi(val)
+	if err != nil {
+		return fmt.Errorf("structconf: invalid %s constraint for field %q: %w", key, field.Path(), err)
+	}
+
+	var s string
+	switch val := field.Value().(type) {
+	case stronf.Secret:
+		s = val.Reveal()
+
+	default:
+		if field.Kind() != reflect.String {
+			return fmt.Errorf("structconf: invalid %s constraint for field %q: unsupported type %q", key, field.Path(), field.Type())
+		}
+
+		s = reflect.ValueOf(val).String()
+	}
+
+	if ok(utf8.RuneCountInString(s), bound) {
+		return nil
+	}
+
+	return &ValidationError{
+		Path:       field.Path(),
+		Constraint: key + ":" + val,
+		Reason:     fmt.Sprintf(reason, bound),
+	}
+}
+
+func validateOneOf(field stronf.Field) error {
+	oneOf, found := field.LookupTag("conf", "oneof")
+	if !found {
+		return nil
+	}
+
+	if slices.Contains(strings.Split(oneOf, "|"), valueString(field)) {
+		return nil
+	}
+
+	return &ValidationError{
+		Path:       field.Path(),
+		Constraint: "oneof:" + oneOf,
+		Reason:     "must be one of " + strings.ReplaceAll(oneOf, "|", ", "),
+	}
+}
+
+func validatePattern(field stronf.Field) error {
+	pattern, found := field.LookupTag("conf", "pattern")
+	if !found {
+		return nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return fmt.Errorf("structconf: invalid pattern constraint for field %q: %w", field.Path(), err)
+	}
+
+	if re.MatchString(valueString(field)) {
+		return nil
+	}
+
+	return &ValidationError{
+		Path:       field.Path(),
+		Constraint: "pattern:" + pattern,
+		Reason:     "must match " + pattern,
+	}
+}
+
+// valueString returns the field's value as a string to compare against, which
+// is the revealed value of a [stronf.Secret], since it prints as redacted.
+func valueString(field stronf.Field) string {
+	if secret, ok := field.Value().(stronf.Secret); ok {
+		return secret.Reveal()
+	}
+
+	return fmt.Sprintf("%v", field.Value())
+}
@@ -0,0 +1,203 @@
+package confhandler_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/kevinfalting/structconf/confhandler"
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+func TestValidate(t *testing.T) {
+	type Level string
+
+	type Server struct {
+		Port    int           `conf:"min:1,max:65535"`
+		Timeout time.Duration `conf:"min:1s,max:1m"`
+		Ratio   float64       `conf:"min:0.5"`
+		Workers uint          `conf:"max:8"`
+	}
+
+	type A struct {
+		Server Server
+		Level  Level         `conf:"oneof:debug|info|warn"`
+		Name   string        `conf:"pattern:^[a-z]+$,minlen:2,maxlen:8"`
+		Token  stronf.Secret `conf:"oneof:a|B|ccc,pattern:^[a-z]+$,maxlen:2"`
+		NoTag  string
+	}
+
+	valid := A{
+		Server: Server{
+			Port:    8080,
+			Timeout: 5 * time.Second,
+			Ratio:   0.5,
+			Workers: 8,
+		},
+		Level: "info",
+		Name:  "app",
+		Token: stronf.NewSecret("a"),
+	}
+
+	testCases := map[string]struct {
+		modify            func(a *A)
+		expectConstraints map[string]string
+	}{
+		"valid": {
+			modify: func(a *A) {},
+		},
+		"below min": {
+			modify: func(a *A) {
+				a.Server.Port = 0
+				a.Server.Timeout = time.Millisecond
+				a.Server.Ratio = 0.25
+			},
+			expectConstraints: map[string]string{
+				"Server.Port":    "min:1",
+				"Server.Timeout": "min:1s",
+				"Server.Ratio":   "min:0.5",
+			},
+		},
+		"above max": {
+			modify: func(a *A) {
+				a.Server.Port = 65536
+				a.Server.Timeout = time.Hour
+				a.Server.Workers = 9
+			},
+			expectConstraints: map[string]string{
+				"Server.Port":    "max:65535",
+				"Server.Timeout": "max:1m",
+				"Server.Workers": "max:8",
+			},
+		},
+		"not one of": {
+			modify: func(a *A) {
+				a.Level = "trace"
+			},
+			expectConstraints: map[string]string{
+				"Level": "oneof:debug|info|warn",
+			},
+		},
+		"pattern": {
+			modify: func(a *A) {
+				a.Name = "ABC"
+			},
+			expectConstraints: map[string]string{
+				"Name": "pattern:^[a-z]+$",
+			},
+		},
+		"too long": {
+			modify: func(a *A) {
+				a.Name = "abcdefghi"
+			},
+			expectConstraints: map[string]string{
+				"Name": "maxlen:8",
+			},
+		},
+		"secret not one of": {
+			modify: func(a *A) {
+				a.Token = stronf.NewSecret("b")
+			},
+			expectConstraints: map[string]string{
+				"Token": "oneof:a|B|ccc",
+			},
+		},
+		"secret pattern": {
+			modify: func(a *A) {
+				a.Token = stronf.NewSecret("B")
+			},
+			expectConstraints: map[string]string{
+				"Token": "pattern:^[a-z]+$",
+			},
+		},
+		"secret too long": {
+			modify: func(a *A) {
+				a.Token = stronf.NewSecret("ccc")
+			},
+			expectConstraints: map[string]string{
+				"Token": "maxlen:2",
+			},
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			a := valid
+			test.modify(&a)
+
+			fields, err := stronf.SettableFields(&a)
+			if err != nil {
+				t.Fatal("failed to SettableFields:", err)
+			}
+
+			got := make(map[string]string)
+			for _, field := range fields {
+				err := confhandler.Validate(field)
+				if err == nil {
+					continue
+				}
+
+				var validationErr *confhandler.ValidationError
+				if !errors.As(err, &validationErr) {
+					t.Fatalf("expected ValidationError, got %v", err)
+				}
+
+				got[validationErr.Path] = validationErr.Constraint
+			}
+
+			if len(got) != len(test.expectConstraints) {
+				t.Errorf("expected %v, got %v", test.expectConstraints, got)
+			}
+
+			for path, constraint := range test.expectConstraints {
+				if got[path] != constraint {
+					t.Errorf("%s: expected %q, got %q", path, constraint, got[path])
+				}
+			}
+		})
+	}
+
+	t.Run("multiple violations on one field", func(t *testing.T) {
+		type B struct {
+			Name string `conf:"minlen:2,pattern:^[a-z]+$"`
+		}
+
+		b := B{Name: "A"}
+		fields, err := stronf.SettableFields(&b)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		err = confhandler.Validate(fields[0])
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok || len(joined.Unwrap()) != 2 {
+			t.Errorf("expected 2 joined errors, got %v", err)
+		}
+	})
+
+	t.Run("invalid constraint", func(t *testing.T) {
+		type B struct {
+			Port int    `conf:"min:one"`
+			Name string `conf:"min:1"`
+			Re   string `conf:"pattern:("`
+		}
+
+		var b B
+		fields, err := stronf.SettableFields(&b)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		for _, field := range fields {
+			err := confhandler.Validate(field)
+			if err == nil {
+				t.Errorf("%s: expected error, got nil", field.Name())
+			}
+
+			var validationErr *confhandler.ValidationError
+			if errors.As(err, &validationErr) {
+				t.Errorf("%s: expected an invalid constraint error, got %v", field.Name(), err)
+			}
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package structconf
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// Description is the effective configuration of a struct, safe to log. The
+// values of secret fields are replaced with [stronf.Redacted].
+type Description struct {
+	Fields []FieldDescription `json:"fields"`
+}
+
+// FieldDescription describes the effective value of a single field.
+type FieldDescription struct {
+	// Path is the full path to the field from the root struct.
+	Path string `json:"path"`
+
+	// Type is the Go type of the field.
+	Type string `json:"type"`
+
+	// Value is the field's value, or [stronf.Redacted] if it's secret.
+	Value string `json:"value"`
+
+	// Source is where the value came from, if it's known.
+	Source string `json:"source,omitempty"`
+
+	// Secret reports whether the value was redacted.
+	Secret bool `json:"secret,omitempty"`
+}
+
+// Describe returns the [Description] of every settable field in cfg, which must
+// be a pointer to a struct. If a [Report] from [Parse] is provided, the source
+// of each value is included.
+func Describe(cfg any, report *Report) (Description, error) {
+	return DescribeWithTag(cfg, report, "conf")
+}
+
+// DescribeWithTag is like [Describe], but looks up the struct tag under tagKey
+// instead of "conf", for structs parsed with [WithTagKey].
+func DescribeWithTag(cfg any, report *Report, tagKey string) (Description, error) {
+	fields, err := stronf.SettableFieldsWithTag(cfg, tagKey)
+	if err != nil {
+		return Description{}, err
+	}
+
+	description := Description{
+		Fields: make([]FieldDescription, 0, len(fields)),
+	}
+
+	for _, field := range fields {
+		fieldDescription := FieldDescription{
+			Path:   field.Path(),
+			Type:   field.Type().String(),
+			Value:  raw(field.Value()),
+			Secret: field.IsSecret(),
+		}
+
+		if fieldDescription.Secret {
+			fieldDescription.Value = stronf.Redacted
+		}
+
+		if report != nil {
+			if fieldReport, ok := report.Field(field.Path()); ok {
+				fieldDescription.Source = fieldReport.Source
+			}
+		}
+
+		description.Fields = append(description.Fields, fieldDescription)
+	}
+
+	return description, nil
+}
+
+// String renders the description as a table.
+func (d Description) String() string {
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tTYPE\tVALUE\tSOURCE")
+	for _, field := range d.Fields {
+		fmt.Fprintf(tw, "%s\t%s\t%q\t%s\n", field.Path, field.Type, field.Value, field.Source)
+	}
+	tw.Flush()
+
+	return trimLines(sb.String())
+}
+
+// LogValue implements [slog.LogValuer], grouping an attribute for each field by
+// its path.
+func (d Description) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(d.Fields))
+	for _, field := range d.Fields {
+		attrs = append(attrs, slog.String(field.Path, field.Value))
+	}
+
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,148 @@
+package structconf_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kevinfalting/structconf"
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+func ExampleDescribe() {
+	os.Setenv("DB_PASSWORD", "hunter2")
+
+	type Database struct {
+		Host     string `conf:"default:localhost"`
+		Password string `conf:"env:DB_PASSWORD,secret"`
+	}
+
+	type Config struct {
+		Database Database
+	}
+
+	var cfg Config
+	var report structconf.Report
+	if err := structconf.Parse(context.Background(), &cfg, structconf.WithReport(&report)); err != nil {
+		log.Println("failed to Parse:", err)
+	}
+
+	description, err := structconf.Describe(&cfg, &report)
+	if err != nil {
+		log.Println("failed to Describe:", err)
+	}
+
+	fmt.Print(description)
+
+	// Output:
+	// FIELD              TYPE    VALUE         SOURCE
+	// Database.Host      string  "localhost"   default
+	// Database.Password  string  "[REDACTED]"  env
+}
+
+func TestDescribe(t *testing.T) {
+	type Config struct {
+		Name  string
+		Token string `conf:"secret"`
+	}
+
+	cfg := Config{
+		Name:  "app",
+		Token: "hunter2",
+	}
+
+	description, err := structconf.Describe(&cfg, nil)
+	if err != nil {
+		t.Fatal("failed to Describe:", err)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		b, err := json.Marshal(description)
+		if err != nil {
+			t.Fatal("failed to Marshal:", err)
+		}
+
+		expect := `{"fields":[{"path":"Name","type":"string","value":"app"},{"path":"Token","type":"string","value":"[REDACTED]","secret":true}]}`
+		if string(b) != expect {
+			t.Errorf("\nexpected:\n%s\ngot:\n%s", expect, b)
+		}
+	})
+
+	t.Run("slog", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if len(groups) == 0 && a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+		logger.Info("config", "cfg", description)
+
+		expect := "level=INFO msg=config cfg.Name=app cfg.Token=[REDACTED]\n"
+		if buf.String() != expect {
+			t.Errorf("\nexpected:\n%s\ngot:\n%s", expect, buf.String())
+		}
+	})
+
+	t.Run("string", func(t *testing.T) {
+		if strings.Contains(description.String(), "hunter2") {
+			t.Errorf("expected secret to be redacted, got:\n%s", description)
+		}
+	})
+}
+
+func TestDescribeWithTag(t *testing.T) {
+	t.Setenv("DESCRIBE_PASS", "hunter2")
+
+	type Config struct {
+		Pass string `cfg:"env:DESCRIBE_PASS,secret"`
+	}
+
+	var cfg Config
+	var report structconf.Report
+	if err := structconf.Parse(context.Background(), &cfg, structconf.WithTagKey("cfg"), structconf.WithReport(&report)); err != nil {
+		t.Fatal("failed to Parse:", err)
+	}
+
+	description, err := structconf.DescribeWithTag(&cfg, &report, "cfg")
+	if err != nil {
+		t.Fatal("failed to DescribeWithTag:", err)
+	}
+
+	expect := structconf.Description{
+		Fields: []structconf.FieldDescription{
+			{Path: "Pass", Type: "string", Value: stronf.Redacted, Source: "env", Secret: true},
+		},
+	}
+
+	if !reflect.DeepEqual(expect, description) {
+		t.Errorf("expected %+v, got %+v", expect, description)
+	}
+}
+
+func TestParse_secretErrors(t *testing.T) {
+	t.Setenv("SECRET_PORT", "hunter2")
+
+	type Config struct {
+		Port int `conf:"env:SECRET_PORT,secret"`
+	}
+
+	var cfg Config
+	err := structconf.Parse(context.Background(), &cfg)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("expected secret to be redacted from error, got %q", err)
+	}
+}
@@ -0,0 +1,91 @@
+package structconf
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// FieldChange describes a field whose value differs between two structs. The
+// values of secret fields are replaced with [stronf.Redacted].
+type FieldChange struct {
+	// Path is the full path to the field from the root struct.
+	Path string `json:"path"`
+
+	// Old is the field's value in the first struct.
+	Old string `json:"old"`
+
+	// New is the field's value in the second struct.
+	New string `json:"new"`
+
+	// OldSource is where the old value came from, if it's known.
+	OldSource string `json:"old_source,omitempty"`
+
+	// NewSource is where the new value came from, if it's known.
+	NewSource string `json:"new_source,omitempty"`
+
+	// Secret reports whether the values were redacted.
+	Secret bool `json:"secret,omitempty"`
+}
+
+// Diff returns a [FieldChange] for every settable field whose value differs
+// between a and b, which must be pointers to structs of the same type. If the
+// [Report]'s from [Parse] are provided, the source of each value is included.
+func Diff(a, b any, reportA, reportB *Report) ([]FieldChange, error) {
+	return DiffWithTag(a, b, reportA, reportB, "conf")
+}
+
+// DiffWithTag is like [Diff], but looks up the struct tag under tagKey instead
+// of "conf", for structs parsed with [WithTagKey].
+func DiffWithTag(a, b any, reportA, reportB *Report, tagKey string) ([]FieldChange, error) {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return nil, fmt.Errorf("structconf: cannot diff %T and %T", a, b)
+	}
+
+	fieldsA, err := stronf.SettableFieldsWithTag(a, tagKey)
+	if err != nil {
+		return nil, err
+	}
+
+	fieldsB, err := stronf.SettableFieldsWithTag(b, tagKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []FieldChange
+	for i, field := range fieldsB {
+		old := fieldsA[i]
+		if reflect.DeepEqual(old.Value(), field.Value()) {
+			continue
+		}
+
+		change := FieldChange{
+			Path:   field.Path(),
+			Old:    raw(old.Value()),
+			New:    raw(field.Value()),
+			Secret: field.IsSecret(),
+		}
+
+		if change.Secret {
+			change.Old = stronf.Redacted
+			change.New = stronf.Redacted
+		}
+
+		if reportA != nil {
+			if fieldReport, ok := reportA.Field(field.Path()); ok {
+				change.OldSource = fieldReport.Source
+			}
+		}
+
+		if reportB != nil {
+			if fieldReport, ok := reportB.Field(field.Path()); ok {
+				change.NewSource = fieldReport.Source
+			}
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,126 @@
+package structconf_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/kevinfalting/structconf"
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+func TestDiff(t *testing.T) {
+	type Database struct {
+		Host     string `conf:"env:DIFF_DB_HOST,default:localhost"`
+		Password string `conf:"env:DIFF_DB_PASSWORD,secret"`
+	}
+
+	type Config struct {
+		Port     int `conf:"default:8080"`
+		Database Database
+	}
+
+	t.Run("changed fields", func(t *testing.T) {
+		ctx := context.Background()
+
+		t.Setenv("DIFF_DB_PASSWORD", "hunter2")
+
+		var old Config
+		var oldReport structconf.Report
+		if err := structconf.Parse(ctx, &old, structconf.WithReport(&oldReport)); err != nil {
+			t.Fatal("failed to Parse:", err)
+		}
+
+		t.Setenv("DIFF_DB_HOST", "db.internal")
+		t.Setenv("DIFF_DB_PASSWORD", "hunter3")
+
+		var new Config
+		var newReport structconf.Report
+		if err := structconf.Parse(ctx, &new, structconf.WithReport(&newReport)); err != nil {
+			t.Fatal("failed to Parse:", err)
+		}
+
+		changes, err := structconf.Diff(&old, &new, &oldReport, &newReport)
+		if err != nil {
+			t.Fatal("failed to Diff:", err)
+		}
+
+		expect := []structconf.FieldChange{
+			{
+				Path:      "Database.Host",
+				Old:       "localhost",
+				New:       "db.internal",
+				OldSource: "default",
+				NewSource: "env",
+			},
+			{
+				Path:      "Database.Password",
+				Old:       stronf.Redacted,
+				New:       stronf.Redacted,
+				OldSource: "env",
+				NewSource: "env",
+				Secret:    true,
+			},
+		}
+
+		if !reflect.DeepEqual(changes, expect) {
+			t.Errorf("expected %+v, got %+v", expect, changes)
+		}
+	})
+
+	t.Run("without reports", func(t *testing.T) {
+		old := Config{Port: 8080}
+		new := Config{Port: 9090}
+
+		changes, err := structconf.Diff(&old, &new, nil, nil)
+		if err != nil {
+			t.Fatal("failed to Diff:", err)
+		}
+
+		expect := []structconf.FieldChange{{Path: "Port", Old: "8080", New: "9090"}}
+		if !reflect.DeepEqual(changes, expect) {
+			t.Errorf("expected %+v, got %+v", expect, changes)
+		}
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		var old, new Config
+
+		changes, err := structconf.Diff(&old, &new, nil, nil)
+		if err != nil {
+			t.Fatal("failed to Diff:", err)
+		}
+
+		if changes != nil {
+			t.Errorf("expected no changes, got %+v", changes)
+		}
+	})
+
+	t.Run("different types", func(t *testing.T) {
+		var old Config
+		var new Database
+
+		if _, err := structconf.Diff(&old, &new, nil, nil); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("tag key", func(t *testing.T) {
+		type Config struct {
+			Pass string `cfg:"secret"`
+		}
+
+		old := Config{Pass: "hunter2"}
+		new := Config{Pass: "hunter3"}
+
+		changes, err := structconf.DiffWithTag(&old, &new, nil, nil, "cfg")
+		if err != nil {
+			t.Fatal("failed to DiffWithTag:", err)
+		}
+
+		expect := []structconf.FieldChange{{Path: "Pass", Old: stronf.Redacted, New: stronf.Redacted, Secret: true}}
+		if !reflect.DeepEqual(changes, expect) {
+			t.Errorf("expected %+v, got %+v", expect, changes)
+		}
+	})
+}
@@ -0,0 +1,19 @@
+package structconf
+
+// SetSignalsNotified replaces the function called once
+// [Watcher.ReloadOnSignal] has registered for its signals, returning a
+// function to restore it. It's exposed for tests only.
+func SetSignalsNotified(fn func()) (restore func()) {
+	previous := signalsNotified
+	signalsNotified = fn
+	return func() { signalsNotified = previous }
+}
+
+// SetWatchStarted replaces the function called once [Watcher.Watch] has
+// recorded its files, returning a function to restore it. It's exposed for
+// tests only.
+func SetWatchStarted(fn func()) (restore func()) {
+	previous := watchStarted
+	watchStarted = fn
+	return func() { watchStarted = previous }
+}
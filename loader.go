@@ -0,0 +1,317 @@
+package structconf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"reflect"
+	"strings"
+
+	"github.com/kevinfalting/structconf/confhandler"
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// Loader holds a handler chain built once from its options, so that it can
+// [Loader.Load] configuration into structs as many times as needed, such as
+// when reloading, in tests, or when sharing one setup across binaries.
+type Loader struct {
+	opt         option
+	optionFuncs []optionFunc
+	envHandler  confhandler.EnvironmentVariable
+	flagHandler *confhandler.Flag
+	handler     stronf.HandleFunc
+
+	registered []registration
+}
+
+// registration is a struct registered with [Loader.Register].
+type registration struct {
+	namespace string
+	cfg       any
+	loader    *Loader
+}
+
+// NewLoader returns a [Loader] with its handler chain built from the options,
+// see [WithHandlers] for how the chain is computed. An error is returned if the
+// options can't be used together.
+func NewLoader(optionFuncs ...optionFunc) (*Loader, error) {
+	loader := Loader{
+		opt: option{
+			tagKey: "conf",
+		},
+		optionFuncs: optionFuncs,
+	}
+
+	for _, optionFunc := range optionFuncs {
+		optionFunc(&loader.opt)
+	}
+
+	var sources []Handler
+
+	if loader.opt.signedFile != nil {
+		// The file is verified on every load, so the handler reading it is
+		// passed through the context.
+		sources = append(sources, Handler{Name: "file", Handle: handleSignedFile})
+	}
+
+	loader.envHandler = confhandler.EnvironmentVariable{
+		Unset: loader.opt.unsetenv,
+	}
+
+	sources = append(sources, Handler{Name: "env", Handle: loader.envHandler.Handle})
+
+	if loader.opt.flagSet != nil {
+		loader.flagHandler = confhandler.NewFlag(loader.opt.flagSet)
+		loader.flagHandler.Namespace = loader.opt.flagNamespace
+		sources = append(sources, Handler{Name: "flag", Handle: loader.handleFlag})
+	}
+
+	sources = append(sources, Handler{Name: "default", Handle: confhandler.Default{}.Handle})
+
+	handlers, err := buildChain(sources, loader.opt.chain)
+	if err != nil {
+		return nil, err
+	}
+
+	if loader.opt.interpolate {
+		handlers = append(handlers, confhandler.Interpolate{}.Handle)
+	}
+
+	if loader.opt.reference != nil {
+		handlers = append(handlers, loader.opt.reference.Handle)
+	}
+
+	if loader.opt.encrypted != nil {
+		handlers = append(handlers, loader.opt.encrypted.Handle)
+	}
+
+	if loader.opt.prompt != nil {
+		handlers = append(handlers, stronf.NamedHandler("prompt", loader.opt.prompt.Handle))
+	}
+
+	handlers = append(handlers, confhandler.Required{}.Handle)
+
+	loader.handler = stronf.CombineHandlers(handlers...)
+
+	return &loader, nil
+}
+
+// Load will set the settable fields in cfg, which must be a pointer to a
+// struct, the same way as [Parse]. Flags are defined the first time a struct
+// is loaded and reused after that. A [Report] passed with [WithReport] is
+// overwritten by every load, so a Loader with a report must not be used
+// concurrently.
+func (l *Loader) Load(ctx context.Context, cfg any) error {
+	opt := l.opt
+
+	cfgFields, err := stronf.SettableFieldsWithTag(cfg, opt.tagKey)
+	if err != nil {
+		return err
+	}
+
+	shadow := shadowCopy(cfg, cfgFields)
+
+	fields, err := stronf.SettableFieldsWithTag(shadow.Interface(), opt.tagKey)
+	if err != nil {
+		return err
+	}
+
+	if opt.signedFile != nil {
+		data, err := confhandler.VerifyFile(opt.signedFile.path, opt.signedFile.trusted...)
+		if err != nil {
+			return err
+		}
+
+		ctx = context.WithValue(ctx, signedFileKey{}, confhandler.NewStdin(bytes.NewReader(data)))
+	}
+
+	if l.flagHandler != nil {
+		if err := l.flagHandler.DefineFlags(fields); err != nil {
+			return err
+		}
+	}
+
+	if opt.report == nil && opt.logger != nil {
+		opt.report = &Report{}
+	}
+
+	var provenance *stronf.Provenance
+	if opt.report != nil {
+		provenance = stronf.NewProvenance()
+		ctx = stronf.ContextWithProvenance(ctx, provenance)
+		opt.report.Fields = nil
+		opt.report.Unsetenv = nil
+	}
+
+	for _, field := range fields {
+		var final any
+		finalHandler := func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+			val, err := l.handler(ctx, field, proposedValue)
+			final = val
+			return val, err
+		}
+
+		if err := field.Parse(ctx, finalHandler); err != nil {
+			return err
+		}
+
+		if opt.report != nil {
+			opt.report.Fields = append(opt.report.Fields, newFieldReport(field, provenance.Candidates(field), final))
+		}
+	}
+
+	var errs []error
+	conditional := confhandler.NewConditional(fields)
+	for _, field := range fields {
+		if err := confhandler.Validate(field); err != nil {
+			errs = append(errs, err)
+		}
+
+		// The handler is called directly, since parsing the field again would
+		// run its validators twice.
+		if _, err := conditional.Handle(ctx, field, nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := stronf.ValidateStructs(shadow.Interface()); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	if opt.dryRun != nil {
+		*opt.dryRun = shadow.Interface()
+		return nil
+	}
+
+	commitFields(cfg, shadow, fields)
+
+	if !opt.deferUnsetenv {
+		if err := l.unsetenv(fields, opt.report); err != nil {
+			return err
+		}
+	}
+
+	if opt.logger != nil {
+		attrs := make([]slog.Attr, 0, len(opt.report.Fields))
+		for _, field := range opt.report.Fields {
+			attrs = append(attrs, slog.Group(field.Path, slog.String("source", field.Source), slog.String("value", field.Raw)))
+		}
+
+		opt.logger.LogAttrs(ctx, slog.LevelDebug, "structconf: loaded configuration", attrs...)
+	}
+
+	return nil
+}
+
+// unsetenv unsets the environment variable of each field that should be,
+// listing them in the report if it's not nil.
+func (l *Loader) unsetenv(fields []stronf.Field, report *Report) error {
+	for _, field := range fields {
+		unset, err := l.envHandler.Unsetenv(field)
+		if err != nil {
+			return err
+		}
+
+		if len(unset) != 0 && report != nil {
+			report.Unsetenv = append(report.Unsetenv, unset)
+		}
+	}
+
+	return nil
+}
+
+// handleFlag is the handler for the "flag" source. Unlike
+// [confhandler.Flag.Handle], it only proposes the value of a flag that was set,
+// so that a flag's default is credited to the "default" source.
+func (l *Loader) handleFlag(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+	val, err := l.flagHandler.Handle(ctx, field, proposedValue)
+	if err != nil {
+		return nil, err
+	}
+
+	flagName, ok := l.flagHandler.FlagName(field)
+	if !ok {
+		return proposedValue, nil
+	}
+
+	set := false
+	l.opt.flagSet.Visit(func(f *flag.Flag) {
+		if f.Name == flagName {
+			set = true
+		}
+	})
+
+	if !set {
+		return proposedValue, nil
+	}
+
+	return val, nil
+}
+
+type signedFileKey struct{}
+
+// handleSignedFile is the handler for the "file" source, reading from the
+// verified file of the current load.
+func handleSignedFile(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+	stdin, ok := ctx.Value(signedFileKey{}).(*confhandler.Stdin)
+	if !ok {
+		return proposedValue, nil
+	}
+
+	return stdin.Handle(ctx, field, proposedValue)
+}
+
+// shadowCopy returns a pointer to a copy of the struct cfg points to. The
+// storage of settable map and slice fields, which can only be set through
+// [encoding.TextUnmarshaler] or [encoding.BinaryUnmarshaler], is copied too, so
+// that parsing the copy can't write into cfg's storage.
+func shadowCopy(cfg any, fields []stronf.Field) reflect.Value {
+	shadow := reflect.New(reflect.TypeOf(cfg).Elem())
+	shadow.Elem().Set(reflect.ValueOf(cfg).Elem())
+
+	for _, field := range fields {
+		rVal := fieldByPath(shadow.Elem(), field.Path())
+
+		switch {
+		case field.Kind() == reflect.Map && !rVal.IsNil():
+			rCopy := reflect.MakeMapWithSize(rVal.Type(), rVal.Len())
+			iter := rVal.MapRange()
+			for iter.Next() {
+				rCopy.SetMapIndex(iter.Key(), iter.Value())
+			}
+			rVal.Set(rCopy)
+
+		case field.Kind() == reflect.Slice && !rVal.IsNil():
+			rCopy := reflect.MakeSlice(rVal.Type(), rVal.Len(), rVal.Len())
+			reflect.Copy(rCopy, rVal)
+			rVal.Set(rCopy)
+		}
+	}
+
+	return shadow
+}
+
+// commitFields sets each of the fields in cfg to its value in shadow, leaving
+// the rest of cfg alone.
+func commitFields(cfg any, shadow reflect.Value, fields []stronf.Field) {
+	rVal := reflect.ValueOf(cfg).Elem()
+	for _, field := range fields {
+		fieldByPath(rVal, field.Path()).Set(fieldByPath(shadow.Elem(), field.Path()))
+	}
+}
+
+// fieldByPath returns the field of the struct rVal at path, the full path to
+// the field from the root struct as returned by [stronf.Field.Path].
+func fieldByPath(rVal reflect.Value, path string) reflect.Value {
+	for _, name := range strings.Split(path, ".") {
+		rVal = rVal.FieldByName(name)
+	}
+
+	return rVal
+}
@@ -0,0 +1,158 @@
+package structconf_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"flag"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kevinfalting/structconf"
+	"github.com/kevinfalting/structconf/confhandler"
+)
+
+func TestLoader(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("load repeatedly with flags", func(t *testing.T) {
+		type Config struct {
+			Port int `conf:"env:LOADER_PORT,flag:port,default:8080"`
+		}
+
+		// The flag handler parses os.Args when the flag set hasn't been parsed
+		// yet.
+		args := os.Args
+		os.Args = []string{"test"}
+		t.Cleanup(func() { os.Args = args })
+
+		fset := flag.NewFlagSet("test", flag.ContinueOnError)
+		loader, err := structconf.NewLoader(structconf.WithFlagSet(fset))
+		if err != nil {
+			t.Fatal("failed to NewLoader:", err)
+		}
+
+		var first Config
+		if err := loader.Load(ctx, &first); err != nil {
+			t.Fatal("failed to Load:", err)
+		}
+
+		if err := fset.Parse([]string{"-port", "9090"}); err != nil {
+			t.Fatal("failed to Parse flags:", err)
+		}
+
+		var second Config
+		if err := loader.Load(ctx, &second); err != nil {
+			t.Fatal("failed to Load:", err)
+		}
+
+		if first.Port != 8080 || second.Port != 9090 {
+			t.Errorf("expected 8080 and 9090, got %d and %d", first.Port, second.Port)
+		}
+	})
+
+	t.Run("signed file is verified on every load", func(t *testing.T) {
+		pub, priv, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatal("failed to GenerateKey:", err)
+		}
+
+		path := filepath.Join(t.TempDir(), "config.json")
+		write := func(data string) {
+			if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+				t.Fatal("failed to WriteFile:", err)
+			}
+
+			if err := confhandler.SignFile(path, priv); err != nil {
+				t.Fatal("failed to SignFile:", err)
+			}
+		}
+
+		type Config struct {
+			Host string `conf:"key:host"`
+		}
+
+		loader, err := structconf.NewLoader(structconf.WithSignedFile(path, pub))
+		if err != nil {
+			t.Fatal("failed to NewLoader:", err)
+		}
+
+		for _, host := range []string{"first.internal", "second.internal"} {
+			write(`{"host": "` + host + `"}`)
+
+			var cfg Config
+			if err := loader.Load(ctx, &cfg); err != nil {
+				t.Fatal("failed to Load:", err)
+			}
+
+			if cfg.Host != host {
+				t.Errorf("expected %q, got %q", host, cfg.Host)
+			}
+		}
+	})
+
+	t.Run("tag key", func(t *testing.T) {
+		t.Setenv("LOADER_NAME", "app")
+
+		type Config struct {
+			Name string `cfg:"env:LOADER_NAME"`
+			Port int    `cfg:"default:8080" conf:"default:9090"`
+		}
+
+		loader, err := structconf.NewLoader(structconf.WithTagKey("cfg"))
+		if err != nil {
+			t.Fatal("failed to NewLoader:", err)
+		}
+
+		var cfg Config
+		if err := loader.Load(ctx, &cfg); err != nil {
+			t.Fatal("failed to Load:", err)
+		}
+
+		if cfg != (Config{Name: "app", Port: 8080}) {
+			t.Errorf("unexpected struct %+v", cfg)
+		}
+	})
+
+	t.Run("logger", func(t *testing.T) {
+		t.Setenv("LOADER_TOKEN", "hunter2")
+
+		type Config struct {
+			Token string `conf:"env:LOADER_TOKEN,secret"`
+			Port  int    `conf:"default:8080"`
+		}
+
+		var buf bytes.Buffer
+		logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		loader, err := structconf.NewLoader(structconf.WithLogger(logger))
+		if err != nil {
+			t.Fatal("failed to NewLoader:", err)
+		}
+
+		var cfg Config
+		if err := loader.Load(ctx, &cfg); err != nil {
+			t.Fatal("failed to Load:", err)
+		}
+
+		out := buf.String()
+		if strings.Contains(out, "hunter2") {
+			t.Errorf("expected secret to be redacted, got %q", out)
+		}
+
+		for _, want := range []string{"Token.source=env", "Port.source=default", "Port.value=8080"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("expected %q in %q", want, out)
+			}
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		if _, err := structconf.NewLoader(structconf.WithPrecedence("vault")); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package structconf
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// Plan is what [Parse] would set each field to, safe to log. The values of
+// secret fields are replaced with [stronf.Redacted].
+type Plan struct {
+	Fields []FieldPlan `json:"fields"`
+}
+
+// FieldPlan describes what [Parse] would set a single field to.
+type FieldPlan struct {
+	// Path is the full path to the field from the root struct.
+	Path string `json:"path"`
+
+	// Current is the field's value before parsing.
+	Current string `json:"current"`
+
+	// Proposed is the value the field would be set to.
+	Proposed string `json:"proposed"`
+
+	// Source is where the proposed value came from.
+	Source string `json:"source"`
+
+	// Secret reports whether the values were redacted.
+	Secret bool `json:"secret,omitempty"`
+}
+
+// DryRun will run [Parse] with the options, including coercion and
+// validation, and return the [Plan] of what it would set without modifying
+// cfg. Fields tagged with unsetenv are not unset. A [Report] passed with
+// [WithReport] is filled in as usual.
+func DryRun(ctx context.Context, cfg any, optionFuncs ...optionFunc) (Plan, error) {
+	opt := option{
+		tagKey: "conf",
+	}
+	for _, optionFunc := range optionFuncs {
+		optionFunc(&opt)
+	}
+
+	report := opt.report
+	if report == nil {
+		report = &Report{}
+	}
+
+	var shadow any
+	optionFuncs = append(slices.Clip(optionFuncs), WithReport(report), func(opt *option) {
+		opt.dryRun = &shadow
+	})
+
+	if err := Parse(ctx, cfg, optionFuncs...); err != nil {
+		return Plan{}, err
+	}
+
+	fields, err := stronf.SettableFieldsWithTag(cfg, opt.tagKey)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	proposed, err := stronf.SettableFieldsWithTag(shadow, opt.tagKey)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	plan := Plan{
+		Fields: make([]FieldPlan, 0, len(fields)),
+	}
+
+	for i, field := range fields {
+		fieldPlan := FieldPlan{
+			Path:     field.Path(),
+			Current:  raw(field.Value()),
+			Proposed: raw(proposed[i].Value()),
+			Secret:   field.IsSecret(),
+		}
+
+		if fieldPlan.Secret {
+			fieldPlan.Current = stronf.Redacted
+			fieldPlan.Proposed = stronf.Redacted
+		}
+
+		if fieldReport, ok := report.Field(field.Path()); ok {
+			fieldPlan.Source = fieldReport.Source
+		}
+
+		plan.Fields = append(plan.Fields, fieldPlan)
+	}
+
+	return plan, nil
+}
+
+// String renders the plan as a table.
+func (p Plan) String() string {
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tCURRENT\tPROPOSED\tSOURCE")
+	for _, field := range p.Fields {
+		fmt.Fprintf(tw, "%s\t%q\t%q\t%s\n", field.Path, field.Current, field.Proposed, field.Source)
+	}
+	tw.Flush()
+
+	return trimLines(sb.String())
+}
@@ -0,0 +1,84 @@
+package structconf_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/kevinfalting/structconf"
+)
+
+func ExampleDryRun() {
+	os.Setenv("PLAN_PORT", "9090")
+	os.Setenv("PLAN_TOKEN", "hunter2")
+
+	type Config struct {
+		Port  int    `conf:"env:PLAN_PORT,default:8080"`
+		Host  string `conf:"default:localhost"`
+		Token string `conf:"env:PLAN_TOKEN,secret"`
+	}
+
+	cfg := Config{Port: 8080}
+	plan, err := structconf.DryRun(context.Background(), &cfg)
+	if err != nil {
+		log.Println("failed to DryRun:", err)
+	}
+
+	fmt.Print(plan)
+	fmt.Println("Port is still", cfg.Port)
+
+	// Output:
+	// FIELD  CURRENT       PROPOSED      SOURCE
+	// Port   "8080"        "9090"        env
+	// Host   ""            "localhost"   default
+	// Token  "[REDACTED]"  "[REDACTED]"  env
+	// Port is still 8080
+}
+
+func TestDryRun(t *testing.T) {
+	t.Run("does not unsetenv", func(t *testing.T) {
+		t.Setenv("PLAN_SECRET", "hunter2")
+
+		type Config struct {
+			Secret string `conf:"env:PLAN_SECRET,unsetenv"`
+		}
+
+		var cfg Config
+		var report structconf.Report
+		plan, err := structconf.DryRun(context.Background(), &cfg, structconf.WithUnsetenv(), structconf.WithReport(&report))
+		if err != nil {
+			t.Fatal("failed to DryRun:", err)
+		}
+
+		if len(plan.Fields) != 1 || plan.Fields[0].Proposed != "hunter2" {
+			t.Errorf("unexpected plan %+v", plan)
+		}
+
+		if _, ok := os.LookupEnv("PLAN_SECRET"); !ok {
+			t.Error("expected PLAN_SECRET to still be set")
+		}
+
+		if _, ok := report.Field("Secret"); !ok {
+			t.Error("expected report to be filled in")
+		}
+
+		if cfg.Secret != "" {
+			t.Errorf("expected struct to be untouched, got %+v", cfg)
+		}
+	})
+
+	t.Run("validation error", func(t *testing.T) {
+		t.Setenv("PLAN_PORT", "0")
+
+		type Config struct {
+			Port int `conf:"env:PLAN_PORT,min:1"`
+		}
+
+		var cfg Config
+		if _, err := structconf.DryRun(context.Background(), &cfg); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
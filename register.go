@@ -0,0 +1,145 @@
+package structconf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// Register adds cfg, which must be a pointer to a struct, to the structs set
+// by [Loader.LoadAll]. This lets structs from separate packages share a single
+// [flag.FlagSet]. When namespace isn't empty, it's prepended to the names of
+// the struct's flags with a dot, such as "db.host". Environment variables are
+// not namespaced, so they must be unique across the registered structs.
+func (l *Loader) Register(namespace string, cfg any) error {
+	if _, err := stronf.SettableFieldsWithTag(cfg, l.opt.tagKey); err != nil {
+		return err
+	}
+
+	for _, reg := range l.registered {
+		if reg.cfg == cfg {
+			return errors.New("structconf: struct is already registered")
+		}
+	}
+
+	optionFuncs := append(slices.Clip(l.optionFuncs), func(opt *option) {
+		opt.flagNamespace = namespace
+		opt.deferUnsetenv = true
+	})
+
+	loader, err := NewLoader(optionFuncs...)
+	if err != nil {
+		return err
+	}
+
+	l.registered = append(l.registered, registration{
+		namespace: namespace,
+		cfg:       cfg,
+		loader:    loader,
+	})
+
+	return nil
+}
+
+// LoadAll will set the fields of every struct registered with
+// [Loader.Register]. The flags of every struct are defined first, and an error
+// is returned if any flag or environment variable is used by more than one of
+// them. Then the [flag.FlagSet], if any, is parsed once from os.Args if it
+// hasn't been parsed yet, and each struct is loaded with [Loader.Load]. The
+// structs are only modified, and their environment variables only unset, once
+// all of them have loaded successfully. A [Report] passed with [WithReport]
+// describes the last struct, and lists the variables unset for every struct.
+func (l *Loader) LoadAll(ctx context.Context) error {
+	flagOwners := make(map[string]string)
+	envOwners := make(map[string]string)
+	copies := make([]reflect.Value, 0, len(l.registered))
+
+	for _, reg := range l.registered {
+		cfgFields, err := stronf.SettableFieldsWithTag(reg.cfg, l.opt.tagKey)
+		if err != nil {
+			return err
+		}
+
+		shadow := shadowCopy(reg.cfg, cfgFields)
+		copies = append(copies, shadow)
+
+		fields, err := stronf.SettableFieldsWithTag(shadow.Interface(), l.opt.tagKey)
+		if err != nil {
+			return err
+		}
+
+		for _, field := range fields {
+			owner := field.Path()
+			if len(reg.namespace) != 0 {
+				owner = reg.namespace + ":" + owner
+			}
+
+			if env, ok := field.LookupTag("conf", "env"); ok {
+				if other, ok := envOwners[env]; ok {
+					return fmt.Errorf("structconf: environment variable %q of field %q collides with field %q", env, owner, other)
+				}
+				envOwners[env] = owner
+			}
+
+			if reg.loader.flagHandler == nil {
+				continue
+			}
+
+			if name, ok := reg.loader.flagHandler.FlagName(field); ok {
+				if other, ok := flagOwners[name]; ok {
+					return fmt.Errorf("structconf: flag %q of field %q collides with field %q", name, owner, other)
+				}
+				flagOwners[name] = owner
+			}
+		}
+
+		if reg.loader.flagHandler != nil {
+			if err := reg.loader.flagHandler.DefineFlags(fields); err != nil {
+				return err
+			}
+		}
+	}
+
+	if l.opt.flagSet != nil && !l.opt.flagSet.Parsed() {
+		if err := l.flagHandler.Parse(os.Args[1:]); err != nil {
+			return err
+		}
+	}
+
+	for i, reg := range l.registered {
+		if err := reg.loader.Load(ctx, copies[i].Interface()); err != nil {
+			if len(reg.namespace) != 0 {
+				return fmt.Errorf("structconf: failed to load namespace %q: %w", reg.namespace, err)
+			}
+
+			return err
+		}
+	}
+
+	fields := make([][]stronf.Field, 0, len(l.registered))
+	for _, reg := range l.registered {
+		regFields, err := stronf.SettableFieldsWithTag(reg.cfg, l.opt.tagKey)
+		if err != nil {
+			return err
+		}
+
+		fields = append(fields, regFields)
+	}
+
+	for i, reg := range l.registered {
+		commitFields(reg.cfg, copies[i], fields[i])
+	}
+
+	for i, reg := range l.registered {
+		if err := reg.loader.unsetenv(fields[i], l.opt.report); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
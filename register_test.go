@@ -0,0 +1,191 @@
+package structconf_test
+
+import (
+	"context"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/kevinfalting/structconf"
+)
+
+func TestLoader_LoadAll(t *testing.T) {
+	type Database struct {
+		Host string `conf:"env:REGISTER_DB_HOST,flag:host,default:localhost"`
+	}
+
+	type Cache struct {
+		Host string `conf:"flag:host,default:localhost"`
+		Size int    `conf:"flag:size,default:64,min:1"`
+	}
+
+	ctx := context.Background()
+
+	t.Run("namespaces share a flag set", func(t *testing.T) {
+		t.Setenv("REGISTER_DB_HOST", "db.internal")
+
+		fset := flag.NewFlagSet("test", flag.ContinueOnError)
+		loader, err := structconf.NewLoader(structconf.WithFlagSet(fset))
+		if err != nil {
+			t.Fatal("failed to NewLoader:", err)
+		}
+
+		var db Database
+		var cache Cache
+		if err := loader.Register("db", &db); err != nil {
+			t.Fatal("failed to Register:", err)
+		}
+
+		if err := loader.Register("cache", &cache); err != nil {
+			t.Fatal("failed to Register:", err)
+		}
+
+		if err := loader.Register("other", &cache); err == nil {
+			t.Error("expected error registering the same struct twice, got nil")
+		}
+
+		// LoadAll parses os.Args once the flags of every struct are defined.
+		args := os.Args
+		os.Args = []string{"test", "-cache.host=cache.internal", "-cache.size=128"}
+		t.Cleanup(func() { os.Args = args })
+
+		if err := loader.LoadAll(ctx); err != nil {
+			t.Fatal("failed to LoadAll:", err)
+		}
+
+		if !fset.Parsed() {
+			t.Error("expected flag set to be parsed")
+		}
+
+		if db.Host != "db.internal" {
+			t.Errorf("expected %q, got %q", "db.internal", db.Host)
+		}
+
+		if cache != (Cache{Host: "cache.internal", Size: 128}) {
+			t.Errorf("unexpected cache %+v", cache)
+		}
+
+		if err := loader.LoadAll(ctx); err != nil {
+			t.Error("failed to LoadAll again:", err)
+		}
+	})
+
+	t.Run("flag collision", func(t *testing.T) {
+		fset := flag.NewFlagSet("test", flag.ContinueOnError)
+		loader, err := structconf.NewLoader(structconf.WithFlagSet(fset))
+		if err != nil {
+			t.Fatal("failed to NewLoader:", err)
+		}
+
+		if err := loader.Register("", &Database{}); err != nil {
+			t.Fatal("failed to Register:", err)
+		}
+
+		if err := loader.Register("", &Cache{}); err != nil {
+			t.Fatal("failed to Register:", err)
+		}
+
+		if err := loader.LoadAll(ctx); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("env collision", func(t *testing.T) {
+		loader, err := structconf.NewLoader()
+		if err != nil {
+			t.Fatal("failed to NewLoader:", err)
+		}
+
+		if err := loader.Register("primary", &Database{}); err != nil {
+			t.Fatal("failed to Register:", err)
+		}
+
+		if err := loader.Register("replica", &Database{}); err != nil {
+			t.Fatal("failed to Register:", err)
+		}
+
+		if err := loader.LoadAll(ctx); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("failure leaves every struct untouched", func(t *testing.T) {
+		fset := flag.NewFlagSet("test", flag.ContinueOnError)
+		loader, err := structconf.NewLoader(structconf.WithFlagSet(fset))
+		if err != nil {
+			t.Fatal("failed to NewLoader:", err)
+		}
+
+		var db Database
+		var cache Cache
+		if err := loader.Register("db", &db); err != nil {
+			t.Fatal("failed to Register:", err)
+		}
+
+		if err := loader.Register("cache", &cache); err != nil {
+			t.Fatal("failed to Register:", err)
+		}
+
+		args := os.Args
+		os.Args = []string{"test", "-db.host=db.internal", "-cache.size=0"}
+		t.Cleanup(func() { os.Args = args })
+
+		if err := loader.LoadAll(ctx); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if db != (Database{}) || cache != (Cache{}) {
+			t.Errorf("expected structs to be untouched, got %+v and %+v", db, cache)
+		}
+	})
+
+	t.Run("unsetenv waits for every struct", func(t *testing.T) {
+		t.Setenv("REGISTER_DB_PASS", "hunter2")
+		t.Setenv("REGISTER_CACHE_SIZE", "not a number")
+
+		type Secrets struct {
+			Pass string `conf:"env:REGISTER_DB_PASS,unsetenv"`
+		}
+
+		type Sizes struct {
+			Size int `conf:"env:REGISTER_CACHE_SIZE"`
+		}
+
+		loader, err := structconf.NewLoader()
+		if err != nil {
+			t.Fatal("failed to NewLoader:", err)
+		}
+
+		var secrets Secrets
+		var sizes Sizes
+		if err := loader.Register("db", &secrets); err != nil {
+			t.Fatal("failed to Register:", err)
+		}
+
+		if err := loader.Register("cache", &sizes); err != nil {
+			t.Fatal("failed to Register:", err)
+		}
+
+		if err := loader.LoadAll(ctx); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if _, ok := os.LookupEnv("REGISTER_DB_PASS"); !ok {
+			t.Fatal("expected REGISTER_DB_PASS to still be set after a failed load")
+		}
+
+		t.Setenv("REGISTER_CACHE_SIZE", "128")
+
+		if err := loader.LoadAll(ctx); err != nil {
+			t.Fatal("failed to LoadAll:", err)
+		}
+
+		if secrets.Pass != "hunter2" || sizes.Size != 128 {
+			t.Errorf("unexpected structs %+v and %+v", secrets, sizes)
+		}
+
+		if _, ok := os.LookupEnv("REGISTER_DB_PASS"); ok {
+			t.Error("expected REGISTER_DB_PASS to be unset")
+		}
+	})
+}
@@ -0,0 +1,128 @@
+package structconf
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// SourceField is the source reported for a field when no handler provided a
+// value, and the value already in the struct was left alone.
+const SourceField = "field"
+
+// Report describes where the value for each field came from after a call to
+// [Parse]. Use [WithReport] to have it filled in.
+type Report struct {
+	Fields []FieldReport
+
+	// Unsetenv are the environment variables that were unset after their value
+	// was consumed.
+	Unsetenv []string
+}
+
+// FieldReport describes where the value for a single field came from.
+type FieldReport struct {
+	// Path is the full path to the field from the root struct.
+	Path string
+
+	// Source is the name of the handler which provided the winning value, or
+	// [SourceField] if no handler provided one.
+	Source string
+
+	// Raw is the winning value as it was proposed, before it was coerced into
+	// the field's type. It's [stronf.Redacted] for secret fields.
+	Raw string
+
+	// Overridden are the values proposed by other handlers that lost to the
+	// winning value, in the order they were proposed.
+	Overridden []stronf.Candidate
+}
+
+// Field returns the [FieldReport] for the field at path.
+func (r Report) Field(path string) (FieldReport, bool) {
+	for _, field := range r.Fields {
+		if field.Path == path {
+			return field, true
+		}
+	}
+
+	return FieldReport{}, false
+}
+
+// String renders the report as a table.
+func (r Report) String() string {
+	var sb strings.Builder
+	tw := tabwriter.NewWriter(&sb, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tSOURCE\tVALUE\tOVERRIDDEN")
+	for _, field := range r.Fields {
+		overridden := make([]string, 0, len(field.Overridden))
+		for _, candidate := range field.Overridden {
+			overridden = append(overridden, fmt.Sprintf("%s=%q", candidate.Source, raw(candidate.Value)))
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%q\t%s\n", field.Path, field.Source, field.Raw, strings.Join(overridden, " "))
+	}
+	tw.Flush()
+
+	return trimLines(sb.String())
+}
+
+func newFieldReport(field stronf.Field, candidates []stronf.Candidate, final any) FieldReport {
+	if field.IsSecret() {
+		redacted := make([]stronf.Candidate, 0, len(candidates))
+		for _, candidate := range candidates {
+			redacted = append(redacted, stronf.Candidate{Source: candidate.Source, Value: stronf.Redacted})
+		}
+		candidates = redacted
+	}
+
+	if final == nil || len(candidates) == 0 {
+		fieldReport := FieldReport{
+			Path:   field.Path(),
+			Source: SourceField,
+			Raw:    raw(field.Value()),
+		}
+
+		if field.IsSecret() {
+			fieldReport.Raw = stronf.Redacted
+		}
+
+		if len(candidates) != 0 {
+			fieldReport.Overridden = candidates
+		}
+
+		return fieldReport
+	}
+
+	winner, overridden := candidates[len(candidates)-1], candidates[:len(candidates)-1]
+	if len(overridden) == 0 {
+		overridden = nil
+	}
+
+	return FieldReport{
+		Path:       field.Path(),
+		Source:     winner.Source,
+		Raw:        raw(winner.Value),
+		Overridden: overridden,
+	}
+}
+
+// trimLines removes the trailing padding tabwriter leaves on the last column.
+func trimLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func raw(val any) string {
+	if b, ok := val.([]byte); ok {
+		return string(b)
+	}
+
+	return fmt.Sprintf("%v", val)
+}
@@ -0,0 +1,157 @@
+package structconf_test
+
+import (
+	"context"
+	"flag"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kevinfalting/structconf"
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+func TestWithReport(t *testing.T) {
+	t.Setenv("REPORT_PORT", "9090")
+
+	type Database struct {
+		Port int    `conf:"env:REPORT_PORT,default:5432"`
+		Host string `conf:"env:REPORT_HOST,default:localhost"`
+	}
+
+	type Config struct {
+		Database Database
+		Name     string
+	}
+
+	cfg := Config{
+		Name: "app",
+	}
+
+	var report structconf.Report
+	if err := structconf.Parse(context.Background(), &cfg, structconf.WithReport(&report)); err != nil {
+		t.Fatal("failed to Parse:", err)
+	}
+
+	expect := structconf.Report{
+		Fields: []structconf.FieldReport{
+			{Path: "Database.Port", Source: "env", Raw: "9090"},
+			{Path: "Database.Host", Source: "default", Raw: "localhost"},
+			{Path: "Name", Source: structconf.SourceField, Raw: "app"},
+		},
+	}
+
+	if !reflect.DeepEqual(expect, report) {
+		t.Errorf("\nexpected:\n%+v\ngot:\n%+v", expect, report)
+	}
+
+	field, ok := report.Field("Database.Host")
+	if !ok {
+		t.Fatal("expected to find Database.Host")
+	}
+
+	if field.Source != "default" {
+		t.Errorf("expected source %q, got %q", "default", field.Source)
+	}
+}
+
+func TestWithReport_flags(t *testing.T) {
+	t.Setenv("REPORT_FLAG_NAME", "api")
+
+	type Config struct {
+		Port int    `conf:"flag:port,default:8080"`
+		Host string `conf:"flag:host,default:localhost"`
+		Name string `conf:"env:REPORT_FLAG_NAME,flag:name,default:app"`
+	}
+
+	fset := flag.NewFlagSet("test", flag.ContinueOnError)
+
+	// The flag handler parses os.Args when the flag set hasn't been parsed
+	// yet.
+	args := os.Args
+	os.Args = []string{"test", "-host=db.internal"}
+	t.Cleanup(func() { os.Args = args })
+
+	var cfg Config
+	var report structconf.Report
+	if err := structconf.Parse(context.Background(), &cfg, structconf.WithFlagSet(fset), structconf.WithReport(&report)); err != nil {
+		t.Fatal("failed to Parse:", err)
+	}
+
+	expect := structconf.Report{
+		Fields: []structconf.FieldReport{
+			{Path: "Port", Source: "default", Raw: "8080"},
+			{Path: "Host", Source: "flag", Raw: "db.internal"},
+			{Path: "Name", Source: "env", Raw: "api"},
+		},
+	}
+
+	if cfg != (Config{Port: 8080, Host: "db.internal", Name: "api"}) {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+
+	if !reflect.DeepEqual(expect, report) {
+		t.Errorf("\nexpected:\n%+v\ngot:\n%+v", expect, report)
+	}
+}
+
+func TestReport_String(t *testing.T) {
+	report := structconf.Report{
+		Fields: []structconf.FieldReport{
+			{
+				Path:   "Port",
+				Source: "flag",
+				Raw:    "1234",
+				Overridden: []stronf.Candidate{
+					{Source: "env", Value: "9090"},
+				},
+			},
+		},
+	}
+
+	got := report.String()
+	for _, expect := range []string{"Port", "flag", `"1234"`, `env="9090"`} {
+		if !strings.Contains(got, expect) {
+			t.Errorf("expected %q to contain %q", got, expect)
+		}
+	}
+}
+
+func TestWithUnsetenv(t *testing.T) {
+	t.Setenv("UNSETENV_TOKEN", "hunter2")
+	t.Setenv("UNSETENV_NAME", "app")
+
+	type Config struct {
+		Token string `conf:"env:UNSETENV_TOKEN,unsetenv"`
+		Name  string `conf:"env:UNSETENV_NAME"`
+	}
+
+	t.Run("tagged", func(t *testing.T) {
+		var cfg Config
+		var report structconf.Report
+		if err := structconf.Parse(context.Background(), &cfg, structconf.WithReport(&report)); err != nil {
+			t.Fatal("failed to Parse:", err)
+		}
+
+		if cfg.Token != "hunter2" {
+			t.Errorf("expected %q, got %q", "hunter2", cfg.Token)
+		}
+
+		if !reflect.DeepEqual([]string{"UNSETENV_TOKEN"}, report.Unsetenv) {
+			t.Errorf("expected UNSETENV_TOKEN to be unset, got %v", report.Unsetenv)
+		}
+	})
+
+	t.Run("all", func(t *testing.T) {
+		var cfg Config
+		var report structconf.Report
+		if err := structconf.Parse(context.Background(), &cfg, structconf.WithUnsetenv(), structconf.WithReport(&report)); err != nil {
+			t.Fatal("failed to Parse:", err)
+		}
+
+		if !reflect.DeepEqual([]string{"UNSETENV_NAME"}, report.Unsetenv) {
+			t.Errorf("expected UNSETENV_NAME to be unset, got %v", report.Unsetenv)
+		}
+	})
+}
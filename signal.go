@@ -0,0 +1,77 @@
+package structconf
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+)
+
+// ReloadEvent describes the outcome of a reload triggered by a signal.
+type ReloadEvent struct {
+	// Signal is the signal that triggered the reload.
+	Signal os.Signal
+
+	// Changed is the full path of every field whose value changed. It's empty
+	// when the reload failed.
+	Changed []string
+
+	// Err is the reason the reload failed, in which case the previous
+	// snapshot was kept.
+	Err error
+}
+
+// LogValue implements [slog.LogValuer], so the event can be logged directly.
+func (e ReloadEvent) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("signal", e.Signal.String()),
+		slog.Any("changed", e.Changed),
+	}
+
+	if e.Err != nil {
+		attrs = append(attrs, slog.String("error", e.Err.Error()))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// signalsNotified is called once [Watcher.ReloadOnSignal] has registered for
+// its signals, so that tests know when it's safe to send them.
+var signalsNotified = func() {}
+
+// ReloadOnSignal will [Value.Reload] every time one of the signals is
+// received, defaulting to SIGHUP on Unix systems, which is the convention for
+// daemons. Elsewhere, the signals must be provided. The outcome of each reload
+// is passed to fn, if it's not nil. It blocks until ctx is done, returning the
+// context's error.
+func (w *Watcher[T]) ReloadOnSignal(ctx context.Context, fn func(ReloadEvent), sigs ...os.Signal) error {
+	if len(sigs) == 0 {
+		sigs = defaultReloadSignals
+	}
+
+	if len(sigs) == 0 {
+		return errors.New("structconf: no signals to reload on")
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, sigs...)
+	defer signal.Stop(sigCh)
+
+	signalsNotified()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case sig := <-sigCh:
+			event := ReloadEvent{Signal: sig}
+			event.Changed, event.Err = w.reload(ctx)
+
+			if fn != nil {
+				fn(event)
+			}
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package structconf
+
+import "os"
+
+// defaultReloadSignals are used by [Watcher.ReloadOnSignal] when no signals
+// are provided. There's no conventional reload signal outside of Unix.
+var defaultReloadSignals []os.Signal
@@ -0,0 +1,107 @@
+//go:build unix
+
+package structconf_test
+
+import (
+	"context"
+	"slices"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/kevinfalting/structconf"
+)
+
+func TestWatcher_ReloadOnSignal(t *testing.T) {
+	t.Setenv("SIGNAL_NAME", "first")
+
+	type Config struct {
+		Name    string `conf:"env:SIGNAL_NAME,minlen:2"`
+		Retries int    `conf:"env:SIGNAL_RETRIES,default:3"`
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watcher, err := structconf.NewWatcher(ctx, Config{})
+	if err != nil {
+		t.Fatal("failed to NewWatcher:", err)
+	}
+
+	notified := make(chan struct{})
+	defer structconf.SetSignalsNotified(func() { close(notified) })()
+
+	events := make(chan structconf.ReloadEvent, 1)
+	done := make(chan error)
+	go func() {
+		done <- watcher.ReloadOnSignal(ctx, func(event structconf.ReloadEvent) {
+			events <- event
+		}, syscall.SIGUSR1)
+	}()
+
+	// Sending the signal before it's registered would kill the test binary.
+	select {
+	case <-notified:
+	case err := <-done:
+		t.Fatal("failed to ReloadOnSignal:", err)
+	}
+
+	signal := func(t *testing.T) structconf.ReloadEvent {
+		t.Helper()
+
+		if err := syscall.Kill(syscall.Getpid(), syscall.SIGUSR1); err != nil {
+			t.Fatal("failed to Kill:", err)
+		}
+
+		select {
+		case event := <-events:
+			if event.Signal != syscall.SIGUSR1 {
+				t.Errorf("expected signal %v, got %v", syscall.SIGUSR1, event.Signal)
+			}
+			return event
+
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for reload")
+			return structconf.ReloadEvent{}
+		}
+	}
+
+	t.Run("changed fields", func(t *testing.T) {
+		t.Setenv("SIGNAL_RETRIES", "5")
+
+		event := signal(t)
+		if event.Err != nil {
+			t.Fatal("unexpected error:", event.Err)
+		}
+
+		if !slices.Equal(event.Changed, []string{"Retries"}) {
+			t.Errorf("expected changed %v, got %v", []string{"Retries"}, event.Changed)
+		}
+
+		if got := watcher.Load(); got != (Config{Name: "first", Retries: 5}) {
+			t.Errorf("unexpected snapshot %+v", got)
+		}
+	})
+
+	t.Run("failed reload keeps previous snapshot", func(t *testing.T) {
+		t.Setenv("SIGNAL_NAME", "x")
+
+		event := signal(t)
+		if event.Err == nil {
+			t.Error("expected error, got nil")
+		}
+
+		if event.Changed != nil {
+			t.Errorf("expected no changed fields, got %v", event.Changed)
+		}
+
+		if got := watcher.Load(); got != (Config{Name: "first", Retries: 5}) {
+			t.Errorf("unexpected snapshot %+v", got)
+		}
+	})
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
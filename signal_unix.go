@@ -0,0 +1,12 @@
+//go:build unix
+
+package structconf
+
+import (
+	"os"
+	"syscall"
+)
+
+// defaultReloadSignals are used by [Watcher.ReloadOnSignal] when no signals
+// are provided.
+var defaultReloadSignals = []os.Signal{syscall.SIGHUP}
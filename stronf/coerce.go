@@ -7,8 +7,18 @@ import (
 	"time"
 )
 
-// Coerce will attempt to convert the provided value into the field's type.
+// Coerce will attempt to convert the provided value into the field's type. If
+// the field [Field.IsSecret], the returned error will not contain the value.
 func Coerce(field Field, val any) (any, error) {
+	coerced, err := coerce(field, val)
+	if err != nil {
+		return nil, redact(field, err)
+	}
+
+	return coerced, nil
+}
+
+func coerce(field Field, val any) (any, error) {
 	rVal := reflect.ValueOf(val)
 	if field.unmarshalerFunc != nil {
 		if !rVal.CanConvert(reflect.SliceOf(reflect.TypeOf(byte(0)))) {
@@ -137,6 +147,6 @@ func coerceString(field Field, s string) (any, error) {
 		return uintptr(u), nil
 
 	default:
-		return nil, fmt.Errorf("structconf: unsupported type %q for field %q", field.Kind().String(), field.Value())
+		return nil, fmt.Errorf("structconf: unsupported type %q for field %q", field.Kind().String(), field.Name())
 	}
 }
@@ -11,6 +11,8 @@ import (
 type Field struct {
 	rVal            reflect.Value
 	rStructField    reflect.StructField
+	path            string
+	tagKey          string
 	unmarshalerFunc func([]byte) error
 }
 
@@ -19,6 +21,12 @@ func (f Field) Name() string {
 	return f.rStructField.Name
 }
 
+// Path returns the dot separated names of the struct fields leading to this
+// field from the root struct, such as "Database.Host".
+func (f Field) Path() string {
+	return f.path
+}
+
 // Value returns the value of the struct field.
 func (f Field) Value() any {
 	return f.rVal.Interface()
@@ -45,16 +53,37 @@ func (f Field) set(val any) error {
 		return err
 	}
 
+	validators, err := f.validators()
+	if err != nil {
+		return err
+	}
+
 	if f.unmarshalerFunc != nil {
 		data, ok := val.([]byte)
 		if !ok {
 			return fmt.Errorf("structconf: unmarshalable field %q must be provided a byte slice, got %T", f.Name(), val)
 		}
 
-		if err := f.unmarshalerFunc(data); err != nil {
+		if len(validators) == 0 {
+			if err := f.unmarshalerFunc(data); err != nil {
+				return redact(f, err)
+			}
+
+			return nil
+		}
+
+		// Unmarshal into a copy so the value can be validated before it's set.
+		rCopy := reflect.New(f.Type())
+		rCopy.Elem().Set(f.rVal)
+		if err := unmarshalerFunc(rCopy.Elem())(data); err != nil {
+			return redact(f, err)
+		}
+
+		if err := f.validate(validators, rCopy.Elem().Interface()); err != nil {
 			return err
 		}
 
+		f.rVal.Set(rCopy.Elem())
 		return nil
 	}
 
@@ -64,13 +93,17 @@ func (f Field) set(val any) error {
 		return fmt.Errorf("structconf: type mismatch, expected %q, got %q for field %q", f.Kind(), rVal.Kind(), f.Name())
 	}
 
+	if err := f.validate(validators, val); err != nil {
+		return err
+	}
+
 	f.rVal.Set(rVal)
 	return nil
 }
 
 // Parse will call the handler against the field and set the field to the
 // returned handler value. If the handler returns nil, no change is made to the
-// field.
+// field, and the field's current value is checked by its validators instead.
 func (f Field) Parse(ctx context.Context, handler HandleFunc) error {
 	if handler == nil {
 		return errors.New("structconf: nil handler")
@@ -82,7 +115,12 @@ func (f Field) Parse(ctx context.Context, handler HandleFunc) error {
 	}
 
 	if val == nil {
-		return nil
+		validators, err := f.validators()
+		if err != nil {
+			return err
+		}
+
+		return f.validate(validators, f.Value())
 	}
 
 	if err := f.set(val); err != nil {
@@ -0,0 +1,108 @@
+package stronf
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Candidate is a value proposed for a field by a named handler.
+type Candidate struct {
+	Source string
+	Value  any
+}
+
+// Provenance records the [Candidate]'s proposed by named handlers for each
+// field, in the order they were proposed. It's safe for concurrent use.
+type Provenance struct {
+	mu         sync.Mutex
+	candidates map[string][]Candidate
+}
+
+// NewProvenance returns an initialized [Provenance].
+func NewProvenance() *Provenance {
+	provenance := Provenance{
+		candidates: make(map[string][]Candidate),
+	}
+
+	return &provenance
+}
+
+// Candidates returns the values proposed for the field, keyed by its
+// [Field.Path]. The last [Candidate] is the one that was proposed last.
+func (p *Provenance) Candidates(field Field) []Candidate {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	candidates := p.candidates[field.Path()]
+	return append([]Candidate(nil), candidates...)
+}
+
+func (p *Provenance) record(field Field, candidate Candidate) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.candidates[field.Path()] = append(p.candidates[field.Path()], candidate)
+}
+
+type provenanceKey struct{}
+
+// ContextWithProvenance returns a copy of ctx which named handlers will record
+// their proposed values to.
+func ContextWithProvenance(ctx context.Context, provenance *Provenance) context.Context {
+	return context.WithValue(ctx, provenanceKey{}, provenance)
+}
+
+// ProvenanceFromContext returns the [Provenance] in ctx, or nil if there isn't
+// one.
+func ProvenanceFromContext(ctx context.Context) *Provenance {
+	provenance, _ := ctx.Value(provenanceKey{}).(*Provenance)
+	return provenance
+}
+
+// AllowsSource reports whether a handler with the name may provide the field's
+// value. A field allows every source unless it's tagged with the sources that
+// are allowed, separated by a pipe, such as `conf:"sources:env|file"`.
+func (f Field) AllowsSource(name string) bool {
+	sources, ok := f.LookupTag("conf", "sources")
+	if !ok {
+		return true
+	}
+
+	return slices.Contains(strings.Split(sources, "|"), name)
+}
+
+// NamedHandler returns a handler which will record name as the source of any
+// value the handler proposes to the [Provenance] in the context. A value is
+// only recorded when it differs from the value that was proposed to the
+// handler, so that handlers which pass along the proposed value aren't
+// credited with it. An error is returned if the field doesn't allow the
+// handler to provide its value, see [Field.AllowsSource].
+func NamedHandler(name string, handler HandleFunc) HandleFunc {
+	return func(ctx context.Context, field Field, proposedValue any) (any, error) {
+		result, err := handler(ctx, field, proposedValue)
+		if err != nil {
+			return nil, err
+		}
+
+		if result == nil || reflect.DeepEqual(result, proposedValue) {
+			return result, nil
+		}
+
+		if !field.AllowsSource(name) {
+			return nil, fmt.Errorf("structconf: source %q is not allowed to set field %q", name, field.Name())
+		}
+
+		if provenance := ProvenanceFromContext(ctx); provenance != nil {
+			provenance.record(field, Candidate{
+				Source: name,
+				Value:  result,
+			})
+		}
+
+		return result, nil
+	}
+}
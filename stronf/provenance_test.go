@@ -0,0 +1,133 @@
+package stronf_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+func TestNamedHandler(t *testing.T) {
+	type Config struct {
+		Nested struct {
+			String string
+		}
+	}
+
+	var cfg Config
+	fields, err := stronf.SettableFields(&cfg)
+	if err != nil {
+		t.Fatal("failed to SettableFields:", err)
+	}
+
+	if len(fields) != 1 {
+		t.Fatalf("expected 1 field, got %d", len(fields))
+	}
+
+	if fields[0].Path() != "Nested.String" {
+		t.Errorf("expected path %q, got %q", "Nested.String", fields[0].Path())
+	}
+
+	propose := func(val any) stronf.HandleFunc {
+		return func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+			return val, nil
+		}
+	}
+
+	passThrough := func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+		return proposedValue, nil
+	}
+
+	handler := stronf.CombineHandlers(
+		stronf.NamedHandler("first", propose("one")),
+		stronf.NamedHandler("pass", passThrough),
+		stronf.NamedHandler("second", propose("two")),
+		stronf.NamedHandler("same", propose("two")),
+	)
+
+	t.Run("no provenance in context", func(t *testing.T) {
+		if err := fields[0].Parse(context.Background(), handler); err != nil {
+			t.Fatal("failed to Parse:", err)
+		}
+
+		if cfg.Nested.String != "two" {
+			t.Errorf("expected %q, got %q", "two", cfg.Nested.String)
+		}
+	})
+
+	t.Run("records candidates", func(t *testing.T) {
+		provenance := stronf.NewProvenance()
+		ctx := stronf.ContextWithProvenance(context.Background(), provenance)
+		if err := fields[0].Parse(ctx, handler); err != nil {
+			t.Fatal("failed to Parse:", err)
+		}
+
+		expect := []stronf.Candidate{
+			{Source: "first", Value: "one"},
+			{Source: "second", Value: "two"},
+		}
+
+		got := provenance.Candidates(fields[0])
+		if !reflect.DeepEqual(expect, got) {
+			t.Errorf("\nexpected:\n%+v\ngot:\n%+v", expect, got)
+		}
+	})
+}
+
+func TestNamedHandler_sources(t *testing.T) {
+	type Config struct {
+		Restricted string `conf:"sources:env|file"`
+		Open       string
+	}
+
+	var cfg Config
+	fields, err := stronf.SettableFields(&cfg)
+	if err != nil {
+		t.Fatal("failed to SettableFields:", err)
+	}
+
+	propose := func(val any) stronf.HandleFunc {
+		return func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+			return val, nil
+		}
+	}
+
+	passThrough := func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+		return proposedValue, nil
+	}
+
+	testCases := map[string]struct {
+		handler   stronf.HandleFunc
+		expectErr [2]bool
+	}{
+		"allowed source": {
+			handler: stronf.NamedHandler("env", propose("value")),
+		},
+		"disallowed source": {
+			handler:   stronf.NamedHandler("flag", propose("value")),
+			expectErr: [2]bool{true, false},
+		},
+		"disallowed source passing along the proposed value": {
+			handler: stronf.CombineHandlers(
+				stronf.NamedHandler("file", propose("value")),
+				stronf.NamedHandler("flag", passThrough),
+			),
+		},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for i, field := range fields {
+				err := field.Parse(context.Background(), test.handler)
+				if test.expectErr[i] && err == nil {
+					t.Errorf("%s: expected error, got nil", field.Name())
+				}
+
+				if !test.expectErr[i] && err != nil {
+					t.Errorf("%s: expected no error, got: %v", field.Name(), err)
+				}
+			}
+		})
+	}
+}
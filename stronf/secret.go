@@ -0,0 +1,117 @@
+package stronf
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"strconv"
+)
+
+// Redacted is shown in place of the value of a secret.
+const Redacted = "[REDACTED]"
+
+// IsSecret reports whether the field's value must be kept out of output and
+// error messages. A field is secret when it's tagged with `conf:"secret"` or
+// is a [Secret].
+func (f Field) IsSecret() bool {
+	if f.rVal.IsValid() && f.Type() == reflect.TypeOf(Secret{}) {
+		return true
+	}
+
+	_, secret := f.LookupTag("conf", "secret")
+	return secret
+}
+
+var (
+	_ encoding.TextUnmarshaler = (*Secret)(nil)
+	_ encoding.TextMarshaler   = Secret{}
+	_ json.Marshaler           = Secret{}
+	_ fmt.Formatter            = Secret{}
+	_ fmt.GoStringer           = Secret{}
+	_ fmt.Stringer             = Secret{}
+	_ slog.LogValuer           = Secret{}
+)
+
+// Secret is a string that can't be printed, marshaled, or logged by accident,
+// each of those will produce [Redacted] instead. Use [Secret.Reveal] to get the
+// value. It's configurable like any other field since it implements
+// [encoding.TextUnmarshaler].
+//
+// Copies of a Secret share the same backing bytes, so [Secret.Wipe] will clear
+// the value for every copy.
+type Secret struct {
+	b []byte
+}
+
+// NewSecret returns a [Secret] holding a copy of s.
+func NewSecret(s string) Secret {
+	return Secret{b: []byte(s)}
+}
+
+// Reveal returns the value of the secret.
+func (s Secret) Reveal() string {
+	return string(s.b)
+}
+
+// Wipe overwrites the backing bytes of the secret with zeros and empties it.
+func (s *Secret) Wipe() {
+	clear(s.b)
+	s.b = nil
+}
+
+// UnmarshalText implements [encoding.TextUnmarshaler], keeping a copy of text.
+func (s *Secret) UnmarshalText(text []byte) error {
+	s.b = append([]byte(nil), text...)
+	return nil
+}
+
+// MarshalText implements [encoding.TextMarshaler], returning [Redacted].
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(Redacted), nil
+}
+
+// MarshalJSON implements [json.Marshaler], returning [Redacted] as a JSON
+// string.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(Redacted)
+}
+
+// String implements [fmt.Stringer], returning [Redacted].
+func (s Secret) String() string {
+	return Redacted
+}
+
+// GoString implements [fmt.GoStringer], returning [Redacted].
+func (s Secret) GoString() string {
+	return Redacted
+}
+
+// Format implements [fmt.Formatter], writing [Redacted] for every verb.
+func (s Secret) Format(f fmt.State, verb rune) {
+	io.WriteString(f, Redacted)
+}
+
+// LogValue implements [slog.LogValuer], returning [Redacted].
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(Redacted)
+}
+
+// redact will replace the error with one that can't contain the value of the
+// field if it's secret. The reason for a [strconv.NumError] is kept since it
+// never contains the value.
+func redact(field Field, err error) error {
+	if err == nil || !field.IsSecret() {
+		return err
+	}
+
+	var numErr *strconv.NumError
+	if errors.As(err, &numErr) {
+		return fmt.Errorf("structconf: invalid value for secret field %q: %w", field.Name(), numErr.Err)
+	}
+
+	return fmt.Errorf("structconf: invalid value for secret field %q", field.Name())
+}
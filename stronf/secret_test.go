@@ -0,0 +1,159 @@
+package stronf_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+func TestField_IsSecret(t *testing.T) {
+	type Config struct {
+		Secret    int       `conf:"secret"`
+		NotSecret int       `conf:"env:NOT_SECRET"`
+		Time      time.Time `conf:"secret"`
+	}
+
+	var cfg Config
+	fields, err := stronf.SettableFields(&cfg)
+	if err != nil {
+		t.Fatal("failed to SettableFields:", err)
+	}
+
+	if !fields[0].IsSecret() {
+		t.Error("expected field to be secret")
+	}
+
+	if fields[1].IsSecret() {
+		t.Error("expected field to not be secret")
+	}
+
+	t.Run("coerce error is redacted", func(t *testing.T) {
+		_, err := stronf.Coerce(fields[0], "hunter2")
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("expected value to be redacted, got %q", err)
+		}
+
+		if !errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("expected %v, got %v", strconv.ErrSyntax, err)
+		}
+	})
+
+	t.Run("coerce error is not redacted", func(t *testing.T) {
+		_, err := stronf.Coerce(fields[1], "hunter2")
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if !strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("expected value in error, got %q", err)
+		}
+	})
+
+	t.Run("unmarshaler error is redacted", func(t *testing.T) {
+		err := fields[2].Set([]byte("hunter2"))
+		if err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if strings.Contains(err.Error(), "hunter2") {
+			t.Errorf("expected value to be redacted, got %q", err)
+		}
+	})
+}
+
+func TestSecret(t *testing.T) {
+	type Config struct {
+		Token stronf.Secret `conf:"env:TOKEN"`
+	}
+
+	var cfg Config
+	fields, err := stronf.SettableFields(&cfg)
+	if err != nil {
+		t.Fatal("failed to SettableFields:", err)
+	}
+
+	if len(fields) != 1 {
+		t.Fatalf("expected 1 field, got %d", len(fields))
+	}
+
+	if !fields[0].IsSecret() {
+		t.Error("expected Secret field to be secret")
+	}
+
+	if !fields[0].IsZero() {
+		t.Error("expected unset Secret to be the zero value")
+	}
+
+	if err := fields[0].Set("hunter2"); err != nil {
+		t.Fatal("failed to Set:", err)
+	}
+
+	if cfg.Token.Reveal() != "hunter2" {
+		t.Errorf("expected %q, got %q", "hunter2", cfg.Token.Reveal())
+	}
+
+	t.Run("never printed", func(t *testing.T) {
+		jsonBytes, err := json.Marshal(cfg)
+		if err != nil {
+			t.Fatal("failed to Marshal:", err)
+		}
+
+		textBytes, err := cfg.Token.MarshalText()
+		if err != nil {
+			t.Fatal("failed to MarshalText:", err)
+		}
+
+		var buf bytes.Buffer
+		slog.New(slog.NewTextHandler(&buf, nil)).Info("config", "token", cfg.Token)
+
+		outputs := map[string]string{
+			"%v":      fmt.Sprintf("%v", cfg),
+			"%+v":     fmt.Sprintf("%+v", cfg),
+			"%#v":     fmt.Sprintf("%#v", cfg),
+			"%s":      fmt.Sprintf("%s", cfg.Token),
+			"%q":      fmt.Sprintf("%q", cfg.Token),
+			"%x":      fmt.Sprintf("%x", cfg.Token),
+			"String":  cfg.Token.String(),
+			"json":    string(jsonBytes),
+			"text":    string(textBytes),
+			"slog":    buf.String(),
+			"Println": fmt.Sprintln(cfg.Token),
+		}
+
+		for name, output := range outputs {
+			if strings.Contains(output, "hunter2") {
+				t.Errorf("%s: expected secret to be redacted, got %q", name, output)
+			}
+
+			if !strings.Contains(output, stronf.Redacted) {
+				t.Errorf("%s: expected %q, got %q", name, stronf.Redacted, output)
+			}
+		}
+	})
+
+	t.Run("wipe", func(t *testing.T) {
+		secret := stronf.NewSecret("hunter2")
+		copied := secret
+		secret.Wipe()
+
+		if secret.Reveal() != "" {
+			t.Errorf("expected wiped secret to be empty, got %q", secret.Reveal())
+		}
+
+		if copied.Reveal() != "\x00\x00\x00\x00\x00\x00\x00" {
+			t.Errorf("expected copy to share wiped bytes, got %q", copied.Reveal())
+		}
+	})
+}
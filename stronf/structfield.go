@@ -9,6 +9,17 @@ import (
 // SettableFields returns a slice of all settable struct fields in the provided
 // struct. The provided argument must be a pointer to a struct.
 func SettableFields(v any) ([]Field, error) {
+	return SettableFieldsWithTag(v, "conf")
+}
+
+// SettableFieldsWithTag is like [SettableFields], but the returned fields look
+// up the "conf" tag under tagKey instead, so that handlers work with structs
+// using a different tag key. See [Field.LookupTag].
+func SettableFieldsWithTag(v any, tagKey string) ([]Field, error) {
+	if len(tagKey) == 0 {
+		return nil, errors.New("structconf: empty tag key")
+	}
+
 	rVal := reflect.ValueOf(v)
 	if rVal.Kind() != reflect.Pointer {
 		return nil, errors.New("structconf: must be pointer")
@@ -20,14 +31,14 @@ func SettableFields(v any) ([]Field, error) {
 	}
 
 	var fields []Field
-	if err := settableFields(rVal, &fields); err != nil {
+	if err := settableFields(rVal, "", tagKey, &fields); err != nil {
 		return nil, err
 	}
 
 	return fields, nil
 }
 
-func settableFields(rVal reflect.Value, fields *[]Field) error {
+func settableFields(rVal reflect.Value, prefix, tagKey string, fields *[]Field) error {
 	for i := 0; i < rVal.NumField(); i++ {
 		rValField := rVal.Field(i)
 		rStructField := rVal.Type().Field(i)
@@ -36,11 +47,15 @@ func settableFields(rVal reflect.Value, fields *[]Field) error {
 			continue
 		}
 
+		path := prefix + rStructField.Name
+
 		unmarshaler := unmarshalerFunc(rValField)
 		if unmarshaler != nil {
 			*fields = append(*fields, Field{
 				rVal:            rValField,
 				rStructField:    rStructField,
+				path:            path,
+				tagKey:          tagKey,
 				unmarshalerFunc: unmarshaler,
 			})
 
@@ -61,10 +76,12 @@ func settableFields(rVal reflect.Value, fields *[]Field) error {
 			*fields = append(*fields, Field{
 				rVal:         rValField,
 				rStructField: rStructField,
+				path:         path,
+				tagKey:       tagKey,
 			})
 
 		case reflect.Struct:
-			if err := settableFields(rValField, fields); err != nil {
+			if err := settableFields(rValField, path+".", tagKey, fields); err != nil {
 				return err
 			}
 
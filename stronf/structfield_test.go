@@ -185,3 +185,35 @@ func TestSettableFields(t *testing.T) {
 		}
 	})
 }
+
+func TestSettableFieldsWithTag(t *testing.T) {
+	type X struct {
+		Name string `cfg:"env:NAME,secret" conf:"env:OTHER"`
+		Port int    `conf:"default:8080"`
+	}
+
+	fields, err := stronf.SettableFieldsWithTag(&X{}, "cfg")
+	if err != nil {
+		t.Fatal("failed to SettableFieldsWithTag:", err)
+	}
+
+	if len(fields) != 2 {
+		t.Fatalf("expected 2 fields, got %d", len(fields))
+	}
+
+	if env, _ := fields[0].LookupTag("conf", "env"); env != "NAME" {
+		t.Errorf("expected %q, got %q", "NAME", env)
+	}
+
+	if !fields[0].IsSecret() {
+		t.Error("expected field to be secret")
+	}
+
+	if _, ok := fields[1].LookupTag("conf", "default"); ok {
+		t.Error("expected the conf tag to be ignored")
+	}
+
+	if _, err := stronf.SettableFieldsWithTag(&X{}, ""); err == nil {
+		t.Error("expected error for empty tag key, got nil")
+	}
+}
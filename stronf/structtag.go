@@ -7,8 +7,13 @@ import "strings"
 // Lookup semantics. An optional path can be provided to lookup nested values.
 // Nested values can themselves be maps, each key/val pair separated by a comma.
 // See examples for supported formats. The bool reports if the value was
-// explicitly found at the struct tag path.
+// explicitly found at the struct tag path. The "conf" tag is looked up under
+// the tag key the field was created with, see [SettableFieldsWithTag].
 func (f Field) LookupTag(tag string, path ...string) (string, bool) {
+	if tag == "conf" && len(f.tagKey) != 0 {
+		tag = f.tagKey
+	}
+
 	value, ok := f.rStructField.Tag.Lookup(tag)
 	if !ok {
 		return "", false
@@ -0,0 +1,152 @@
+package stronf
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ValidatorFunc checks a value after it has been coerced into the field's type
+// and before it's set on the field. When no value is proposed for the field,
+// see [Field.Parse], it checks the field's current value instead.
+type ValidatorFunc func(field Field, val any) error
+
+var (
+	validatorsMu sync.RWMutex
+	validators   = map[string]ValidatorFunc{
+		"nonempty": validateNonEmpty,
+	}
+)
+
+// RegisterValidator makes a [ValidatorFunc] available by name to every field
+// tagged with it, such as `conf:"validate:region|nonempty"` for validators
+// separated by a pipe. Registering a name again replaces the validator. The
+// "nonempty" validator is registered by default and rejects the zero value. It
+// panics if the name is empty or contains a pipe, or the validator is nil.
+func RegisterValidator(name string, validator ValidatorFunc) {
+	if len(name) == 0 || strings.Contains(name, "|") {
+		panic(fmt.Sprintf("structconf: invalid validator name %q", name))
+	}
+
+	if validator == nil {
+		panic(fmt.Sprintf("structconf: nil validator %q", name))
+	}
+
+	validatorsMu.Lock()
+	defer validatorsMu.Unlock()
+
+	validators[name] = validator
+}
+
+// validators returns the registered validators named in the field's struct
+// tag, in order.
+func (f Field) validators() ([]namedValidator, error) {
+	names, ok := f.LookupTag("conf", "validate")
+	if !ok {
+		return nil, nil
+	}
+
+	validatorsMu.RLock()
+	defer validatorsMu.RUnlock()
+
+	var fieldValidators []namedValidator
+	for _, name := range strings.Split(names, "|") {
+		validator, ok := validators[name]
+		if !ok {
+			return nil, fmt.Errorf("structconf: unknown validator %q for field %q", name, f.Name())
+		}
+
+		fieldValidators = append(fieldValidators, namedValidator{name: name, validator: validator})
+	}
+
+	return fieldValidators, nil
+}
+
+func (f Field) validate(validators []namedValidator, val any) error {
+	for _, v := range validators {
+		if err := v.validator(f, val); err != nil {
+			return fmt.Errorf("structconf: field %q failed validator %q: %w", f.Name(), v.name, err)
+		}
+	}
+
+	return nil
+}
+
+type namedValidator struct {
+	name      string
+	validator ValidatorFunc
+}
+
+func validateNonEmpty(field Field, val any) error {
+	if reflect.ValueOf(val).IsZero() {
+		return errors.New("must not be empty")
+	}
+
+	return nil
+}
+
+// Validator is implemented by a struct which can validate itself once all of
+// its fields are set, such as checks that involve several fields.
+type Validator interface {
+	Validate() error
+}
+
+// ValidateStructs will call Validate on every struct that implements
+// [Validator], starting with the nested structs reached the same way as
+// [SettableFields] and finishing with the provided struct, so that a struct is
+// validated after all of the structs it contains. The provided argument must
+// be a pointer to a struct. The errors are joined together with [errors.Join].
+func ValidateStructs(v any) error {
+	rVal := reflect.ValueOf(v)
+	if rVal.Kind() != reflect.Pointer {
+		return errors.New("structconf: must be pointer")
+	}
+
+	rVal = rVal.Elem()
+	if rVal.Kind() != reflect.Struct {
+		return errors.New("structconf: must be a struct")
+	}
+
+	var errs []error
+	validateStructs(rVal, "", &errs)
+
+	return errors.Join(errs...)
+}
+
+func validateStructs(rVal reflect.Value, path string, errs *[]error) {
+	for i := 0; i < rVal.NumField(); i++ {
+		rValField := rVal.Field(i)
+		rStructField := rVal.Type().Field(i)
+
+		if !rValField.CanSet() || rValField.Kind() != reflect.Struct || unmarshalerFunc(rValField) != nil {
+			continue
+		}
+
+		fieldPath := rStructField.Name
+		if len(path) != 0 {
+			fieldPath = path + "." + rStructField.Name
+		}
+
+		validateStructs(rValField, fieldPath, errs)
+	}
+
+	if !rVal.CanAddr() {
+		return
+	}
+
+	validator, ok := rVal.Addr().Interface().(Validator)
+	if !ok {
+		return
+	}
+
+	if err := validator.Validate(); err != nil {
+		if len(path) == 0 {
+			*errs = append(*errs, fmt.Errorf("structconf: validation failed: %w", err))
+			return
+		}
+
+		*errs = append(*errs, fmt.Errorf("structconf: validation failed for %q: %w", path, err))
+	}
+}
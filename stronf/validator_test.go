@@ -0,0 +1,199 @@
+package stronf_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+var validateOrder []string
+
+type tlsConfig struct {
+	Cert string
+	Key  string
+}
+
+func (c tlsConfig) Validate() error {
+	validateOrder = append(validateOrder, "TLS")
+	if len(c.Cert) != 0 && len(c.Key) == 0 {
+		return errors.New("key required if cert set")
+	}
+	return nil
+}
+
+type poolConfig struct {
+	MinConns int
+	MaxConns int
+}
+
+func (c *poolConfig) Validate() error {
+	validateOrder = append(validateOrder, "Pool")
+	if c.MinConns > c.MaxConns {
+		return errors.New("min conns must be <= max conns")
+	}
+	return nil
+}
+
+type serverConfig struct {
+	TLS  tlsConfig
+	Pool poolConfig
+	Name string
+}
+
+func (c serverConfig) Validate() error {
+	validateOrder = append(validateOrder, "root")
+	if len(c.Name) == 0 {
+		return errors.New("name required")
+	}
+	return nil
+}
+
+func TestValidateStructs(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		validateOrder = nil
+		cfg := serverConfig{Name: "app"}
+		if err := stronf.ValidateStructs(&cfg); err != nil {
+			t.Error("expected no error, got:", err)
+		}
+
+		expectOrder := []string{"TLS", "Pool", "root"}
+		if !reflect.DeepEqual(expectOrder, validateOrder) {
+			t.Errorf("expected order %v, got %v", expectOrder, validateOrder)
+		}
+	})
+
+	t.Run("all errors joined", func(t *testing.T) {
+		cfg := serverConfig{
+			TLS:  tlsConfig{Cert: "cert.pem"},
+			Pool: poolConfig{MinConns: 10, MaxConns: 1},
+		}
+
+		err := stronf.ValidateStructs(&cfg)
+		joined, ok := err.(interface{ Unwrap() []error })
+		if !ok || len(joined.Unwrap()) != 3 {
+			t.Errorf("expected 3 joined errors, got %v", err)
+		}
+	})
+
+	t.Run("must be pointer to struct", func(t *testing.T) {
+		if err := stronf.ValidateStructs(serverConfig{}); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+}
+
+func TestRegisterValidator(t *testing.T) {
+	regions := []string{"us-east-1", "eu-west-1"}
+	stronf.RegisterValidator("region", func(field stronf.Field, val any) error {
+		s, ok := val.(string)
+		if !ok {
+			return fmt.Errorf("unsupported type %T", val)
+		}
+
+		if !slices.Contains(regions, s) {
+			return errors.New("unknown region")
+		}
+
+		return nil
+	})
+
+	stronf.RegisterValidator("future", func(field stronf.Field, val any) error {
+		if !val.(time.Time).After(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)) {
+			return errors.New("must be after 2000")
+		}
+
+		return nil
+	})
+
+	type Config struct {
+		Region  string    `conf:"validate:nonempty|region"`
+		Retries int       `conf:"validate:nonempty"`
+		Since   time.Time `conf:"validate:future"`
+		Unknown string    `conf:"validate:nope"`
+	}
+
+	testCases := map[string]struct {
+		field     int
+		val       any
+		expect    any
+		expectErr bool
+	}{
+		"valid region":      {field: 0, val: "eu-west-1", expect: "eu-west-1"},
+		"unknown region":    {field: 0, val: "mars-1", expect: "us-east-1", expectErr: true},
+		"empty region":      {field: 0, val: "", expect: "us-east-1", expectErr: true},
+		"nonempty int":      {field: 1, val: "3", expect: 3},
+		"empty int":         {field: 1, val: "0", expect: 1, expectErr: true},
+		"valid time":        {field: 2, val: []byte("2023-11-05T15:04:05Z"), expect: time.Date(2023, 11, 5, 15, 4, 5, 0, time.UTC)},
+		"invalid time":      {field: 2, val: []byte("1999-11-05T15:04:05Z"), expect: time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC), expectErr: true},
+		"unknown validator": {field: 3, val: "anything", expect: "", expectErr: true},
+	}
+
+	for name, test := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg := Config{
+				Region:  "us-east-1",
+				Retries: 1,
+				Since:   time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC),
+			}
+
+			fields, err := stronf.SettableFields(&cfg)
+			if err != nil {
+				t.Fatal("failed to SettableFields:", err)
+			}
+
+			field := fields[test.field]
+			err = field.Set(test.val)
+			if test.expectErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+
+			if !test.expectErr && err != nil {
+				t.Error("expected no error, got:", err)
+			}
+
+			if !reflect.DeepEqual(test.expect, field.Value()) {
+				t.Errorf("expected %v, got %v", test.expect, field.Value())
+			}
+		})
+	}
+}
+
+func TestRegisterValidator_noProposedValue(t *testing.T) {
+	type Config struct {
+		Name string `conf:"validate:nonempty"`
+	}
+
+	noValue := func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+		return nil, nil
+	}
+
+	t.Run("zero value", func(t *testing.T) {
+		var cfg Config
+		fields, err := stronf.SettableFields(&cfg)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		if err := fields[0].Parse(context.Background(), noValue); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("current value", func(t *testing.T) {
+		cfg := Config{Name: "app"}
+		fields, err := stronf.SettableFields(&cfg)
+		if err != nil {
+			t.Fatal("failed to SettableFields:", err)
+		}
+
+		if err := fields[0].Parse(context.Background(), noValue); err != nil {
+			t.Error("expected no error, got:", err)
+		}
+	})
+}
@@ -2,66 +2,168 @@ package structconf
 
 import (
 	"context"
+	"crypto/ed25519"
 	"flag"
+	"log/slog"
 
 	"github.com/kevinfalting/structconf/confhandler"
-	"github.com/kevinfalting/structconf/stronf"
 )
 
 // Parse will set any settable fields in the provided struct based on the
 // results of the default handlers. By default, it checks for environment
-// variables, default, and required tags. Flags are optionally enabled.
+// variables, default, and required tags. Flags are optionally enabled, and the
+// handler chain can be changed with [WithHandlers]. Once every field is set,
+// each field is validated with [confhandler.Validate] and
+// [confhandler.Conditional], then every struct implementing [stronf.Validator]
+// is validated with [stronf.ValidateStructs], and all of the errors are
+// returned together.
+//
+// Every value is resolved into a copy of cfg, and only the settable fields are
+// copied back into cfg once every field has been set and validated, so cfg is
+// left untouched when an error is returned. Settable maps and slices, which
+// unmarshal into their storage, are copied too, one level deep. To parse more
+// than once with the same options, use a [Loader] instead.
 func Parse(ctx context.Context, cfg any, optionFuncs ...optionFunc) error {
-	fields, err := stronf.SettableFields(cfg)
+	loader, err := NewLoader(optionFuncs...)
 	if err != nil {
 		return err
 	}
 
-	var opt option
-	for _, optionFunc := range optionFuncs {
-		optionFunc(&opt)
+	return loader.Load(ctx, cfg)
+}
+
+type option struct {
+	flagSet     *flag.FlagSet
+	interpolate bool
+	reference   *confhandler.Reference
+	encrypted   *confhandler.Encrypted
+	prompt      *confhandler.Prompt
+	report      *Report
+	unsetenv    bool
+	signedFile  *signedFile
+	chain       []chainEdit
+	tagKey      string
+	logger      *slog.Logger
+
+	// flagNamespace is the namespace of the flags of a struct registered with
+	// [Loader.Register].
+	flagNamespace string
+
+	// deferUnsetenv leaves unsetting environment variables to
+	// [Loader.LoadAll], once every registered struct has loaded.
+	deferUnsetenv bool
+
+	// dryRun receives the parsed copy of the struct instead of it being copied
+	// back, see [DryRun].
+	dryRun *any
+}
+
+type signedFile struct {
+	path    string
+	trusted []ed25519.PublicKey
+}
+
+type optionFunc func(opt *option)
+
+// WithFlagSet will signal to use the [confhandler.Flag] and optionally pass it
+// a [flag.FlagSet]. Passing a nil flagset will create and use one similar to
+// the stdlib flagset.
+func WithFlagSet(fset *flag.FlagSet) optionFunc {
+	return func(opt *option) {
+		opt.flagSet = fset
 	}
+}
 
-	handlers := []stronf.HandleFunc{
-		confhandler.EnvironmentVariable{}.Handle,
+// WithInterpolation will expand variables like "${HOME}/.cache/app" in string
+// values from any handler, including the default tag. See
+// [confhandler.Interpolate] for the supported syntax.
+func WithInterpolation() optionFunc {
+	return func(opt *option) {
+		opt.interpolate = true
 	}
+}
 
-	if opt.flagSet != nil {
-		flagHandler := confhandler.NewFlag(opt.flagSet)
-		if err := flagHandler.DefineFlags(fields); err != nil {
-			return err
+// WithReference will resolve values that refer to another location, like
+// "file:///run/secrets/db", using the provided [confhandler.Reference]. Passing
+// a nil reference will use one with the default schemes registered.
+func WithReference(ref *confhandler.Reference) optionFunc {
+	return func(opt *option) {
+		if ref == nil {
+			ref = confhandler.NewReference()
 		}
 
-		handlers = append(handlers, flagHandler.Handle)
+		opt.reference = ref
 	}
+}
 
-	handlers = append(handlers,
-		confhandler.Default{}.Handle,
-		confhandler.Required{}.Handle,
-	)
-
-	handler := stronf.CombineHandlers(handlers...)
+// WithEncrypted will decrypt values starting with [confhandler.EncryptedPrefix]
+// using the provided [confhandler.Encrypted]. Decryption happens after
+// interpolation and references are resolved.
+func WithEncrypted(encrypted *confhandler.Encrypted) optionFunc {
+	return func(opt *option) {
+		opt.encrypted = encrypted
+	}
+}
 
-	for _, field := range fields {
-		if err := field.Parse(ctx, handler); err != nil {
-			return err
+// WithPrompt will prompt for any required field that has no value instead of
+// returning an error, using the provided [confhandler.Prompt]. Passing a nil
+// prompt will use one that reads from stdin and writes to stderr.
+func WithPrompt(prompt *confhandler.Prompt) optionFunc {
+	return func(opt *option) {
+		if prompt == nil {
+			prompt = confhandler.NewPrompt(nil, nil)
 		}
+
+		opt.prompt = prompt
 	}
+}
 
-	return nil
+// WithReport will fill in the provided [Report] with where the value for each
+// field came from. The sources are named "file", "env", "flag", "default", and
+// "prompt", or [SourceField] when no handler provided a value.
+func WithReport(report *Report) optionFunc {
+	return func(opt *option) {
+		opt.report = report
+	}
 }
 
-type option struct {
-	flagSet *flag.FlagSet
+// WithUnsetenv will unset the environment variable of every field once all of
+// the fields have been set, not only the fields with the 'unsetenv' key in the
+// struct tag. The variables that were unset are listed in the [Report].
+func WithUnsetenv() optionFunc {
+	return func(opt *option) {
+		opt.unsetenv = true
+	}
 }
 
-type optionFunc func(opt *option)
+// WithSignedFile will read configuration from the JSON or KEY=VALUE file at
+// path, the same way as [confhandler.Stdin], once its detached signature has
+// been verified against the trusted keys. Values from the file are overridden
+// by every other source except the default tag. A file that fails verification
+// will abort [Parse] with a [*confhandler.SignatureError] before any values are
+// set.
+func WithSignedFile(path string, trusted ...ed25519.PublicKey) optionFunc {
+	return func(opt *option) {
+		opt.signedFile = &signedFile{
+			path:    path,
+			trusted: trusted,
+		}
+	}
+}
 
-// WithFlagSet will signal to use the [confhandler.Flag] and optionally pass it
-// a [flag.FlagSet]. Passing a nil flagset will create and use one similar to
-// the stdlib flagset.
-func WithFlagSet(fset *flag.FlagSet) optionFunc {
+// WithTagKey will look up the struct tag under key instead of "conf", such as
+// `cfg:"env:PORT"`, for every handler. Fields must use [stronf.Field.LookupTag]
+// with the "conf" tag for custom handlers to honor it.
+func WithTagKey(key string) optionFunc {
 	return func(opt *option) {
-		opt.flagSet = fset
+		opt.tagKey = key
+	}
+}
+
+// WithLogger will log the source and value of every field at the debug level
+// once they've all been set, redacting secret fields.
+func WithLogger(logger *slog.Logger) optionFunc {
+	return func(opt *option) {
+		opt.logger = logger
 	}
 }
@@ -2,11 +2,19 @@ package structconf_test
 
 import (
 	"context"
+	"crypto/ed25519"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
 
 	"github.com/kevinfalting/structconf"
+	"github.com/kevinfalting/structconf/confhandler"
+	"github.com/kevinfalting/structconf/stronf"
 )
 
 func ExampleParse() {
@@ -27,3 +35,295 @@ func ExampleParse() {
 	// Output:
 	// {Name:Vikki}
 }
+
+func ExampleWithReference() {
+	os.Setenv("DB_PASSWORD", "env://CI_DB_PASSWORD")
+	os.Setenv("CI_DB_PASSWORD", "hunter2")
+
+	type Config struct {
+		Password string `conf:"env:DB_PASSWORD"`
+	}
+
+	var cfg Config
+
+	if err := structconf.Parse(context.Background(), &cfg, structconf.WithReference(nil)); err != nil {
+		log.Println("failed to Parse:", err)
+	}
+
+	fmt.Printf("%+v\n", cfg)
+
+	// Output:
+	// {Password:hunter2}
+}
+
+func ExampleWithInterpolation() {
+	os.Setenv("APP_ROOT", "/srv")
+
+	type Config struct {
+		CacheDir string `conf:"default:${APP_ROOT}/${APP_NAME:-app}/cache"`
+	}
+
+	var cfg Config
+
+	if err := structconf.Parse(context.Background(), &cfg, structconf.WithInterpolation()); err != nil {
+		log.Println("failed to Parse:", err)
+	}
+
+	fmt.Printf("%+v\n", cfg)
+
+	// Output:
+	// {CacheDir:/srv/app/cache}
+}
+
+func ExampleWithReport() {
+	os.Setenv("LOG_LEVEL", "debug")
+
+	type Config struct {
+		LogLevel string `conf:"env:LOG_LEVEL,default:info"`
+		Port     int    `conf:"default:8080"`
+		Name     string
+	}
+
+	cfg := Config{
+		Name: "app",
+	}
+
+	var report structconf.Report
+	if err := structconf.Parse(context.Background(), &cfg, structconf.WithReport(&report)); err != nil {
+		log.Println("failed to Parse:", err)
+	}
+
+	fmt.Print(report)
+
+	// Output:
+	// FIELD     SOURCE   VALUE    OVERRIDDEN
+	// LogLevel  env      "debug"
+	// Port      default  "8080"
+	// Name      field    "app"
+}
+
+func ExampleWithSignedFile() {
+	dir, err := os.MkdirTemp("", "structconf")
+	if err != nil {
+		log.Println("failed to MkdirTemp:", err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		log.Println("failed to GenerateKey:", err)
+		return
+	}
+
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"database": {"host": "db.internal"}}`), 0o600); err != nil {
+		log.Println("failed to WriteFile:", err)
+		return
+	}
+
+	if err := confhandler.SignFile(path, priv); err != nil {
+		log.Println("failed to SignFile:", err)
+		return
+	}
+
+	type Config struct {
+		Host string `conf:"key:database.host,default:localhost"`
+	}
+
+	var cfg Config
+	if err := structconf.Parse(context.Background(), &cfg, structconf.WithSignedFile(path, pub)); err != nil {
+		log.Println("failed to Parse:", err)
+	}
+
+	fmt.Printf("%+v\n", cfg)
+
+	if err := os.WriteFile(path, []byte(`{"database": {"host": "evil.example.com"}}`), 0o600); err != nil {
+		log.Println("failed to WriteFile:", err)
+		return
+	}
+
+	var sigErr *confhandler.SignatureError
+	err = structconf.Parse(context.Background(), &Config{}, structconf.WithSignedFile(path, pub))
+	fmt.Println(errors.As(err, &sigErr))
+
+	// Output:
+	// {Host:db.internal}
+	// true
+}
+
+func ExampleParse_validation() {
+	os.Setenv("PORT", "0")
+	os.Setenv("LOG_LEVEL", "trace")
+
+	type Config struct {
+		Port     int    `conf:"env:PORT,min:1,max:65535"`
+		LogLevel string `conf:"env:LOG_LEVEL,oneof:debug|info|warn"`
+	}
+
+	var cfg Config
+	err := structconf.Parse(context.Background(), &cfg)
+	fmt.Println(err)
+
+	// Output:
+	// structconf: field "Port" violates "min:1": must be at least 1
+	// structconf: field "LogLevel" violates "oneof:debug|info|warn": must be one of debug, info, warn
+}
+
+func TestParse(t *testing.T) {
+	t.Run("failed parse leaves struct untouched", func(t *testing.T) {
+		t.Setenv("PARSE_NAME", "app")
+		t.Setenv("PARSE_PORT", "not a number")
+
+		type Config struct {
+			Name string `conf:"env:PARSE_NAME"`
+			Port int    `conf:"env:PARSE_PORT"`
+		}
+
+		cfg := Config{Name: "original", Port: 1}
+		if err := structconf.Parse(context.Background(), &cfg); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if cfg != (Config{Name: "original", Port: 1}) {
+			t.Errorf("expected struct to be untouched, got %+v", cfg)
+		}
+	})
+
+	t.Run("failed validation leaves struct untouched", func(t *testing.T) {
+		t.Setenv("PARSE_NAME", "app")
+		t.Setenv("PARSE_PORT", "0")
+
+		type Config struct {
+			Name string `conf:"env:PARSE_NAME"`
+			Port int    `conf:"env:PARSE_PORT,min:1"`
+		}
+
+		cfg := Config{Name: "original", Port: 1}
+		if err := structconf.Parse(context.Background(), &cfg); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if cfg != (Config{Name: "original", Port: 1}) {
+			t.Errorf("expected struct to be untouched, got %+v", cfg)
+		}
+	})
+
+	t.Run("failed parse leaves maps untouched", func(t *testing.T) {
+		t.Setenv("PARSE_LABELS", "team=infra")
+		t.Setenv("PARSE_PORT", "not a number")
+
+		type Config struct {
+			Labels labels `conf:"env:PARSE_LABELS"`
+			Port   int    `conf:"env:PARSE_PORT"`
+		}
+
+		cfg := Config{Labels: labels{"env": "prod"}}
+		if err := structconf.Parse(context.Background(), &cfg); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+
+		if !reflect.DeepEqual(cfg.Labels, labels{"env": "prod"}) {
+			t.Errorf("expected labels to be untouched, got %v", cfg.Labels)
+		}
+	})
+
+	t.Run("successful parse leaves other fields alone", func(t *testing.T) {
+		t.Setenv("PARSE_LABELS", "team=infra")
+
+		type Config struct {
+			Labels   labels `conf:"env:PARSE_LABELS"`
+			Handlers map[string]int
+			loads    int
+		}
+
+		cfg := Config{Handlers: map[string]int{"a": 1}}
+		handlers := cfg.Handlers
+
+		// The handler changes the struct while it's parsed, which must not be
+		// overwritten by the parsed copy.
+		counter := structconf.Handler{Name: "counter", Handle: func(ctx context.Context, field stronf.Field, proposedValue any) (any, error) {
+			cfg.loads++
+			return proposedValue, nil
+		}}
+
+		if err := structconf.Parse(context.Background(), &cfg, structconf.WithHandlerBefore("env", counter)); err != nil {
+			t.Fatal("failed to Parse:", err)
+		}
+
+		if !reflect.DeepEqual(cfg.Labels, labels{"team": "infra"}) {
+			t.Errorf("expected labels to be set, got %v", cfg.Labels)
+		}
+
+		if reflect.ValueOf(cfg.Handlers).Pointer() != reflect.ValueOf(handlers).Pointer() {
+			t.Error("expected the map of an unsettable field to be left alone")
+		}
+
+		if cfg.loads != 1 {
+			t.Errorf("expected 1 load, got %d", cfg.loads)
+		}
+	})
+
+	t.Run("validators run once", func(t *testing.T) {
+		t.Setenv("PARSE_TENANT", "acme")
+
+		var calls int
+		stronf.RegisterValidator("parse_tenant", func(field stronf.Field, val any) error {
+			calls++
+			return nil
+		})
+
+		type Config struct {
+			Tenant string `conf:"env:PARSE_TENANT,validate:parse_tenant"`
+			Region string `conf:"validate:parse_tenant"`
+		}
+
+		var cfg Config
+		if err := structconf.Parse(context.Background(), &cfg); err != nil {
+			t.Fatal("failed to Parse:", err)
+		}
+
+		if calls != 2 {
+			t.Errorf("expected 2 calls, got %d", calls)
+		}
+	})
+
+	t.Run("successful parse sets struct", func(t *testing.T) {
+		t.Setenv("PARSE_NAME", "app")
+		t.Setenv("PARSE_PORT", "8080")
+
+		type Config struct {
+			Name  string `conf:"env:PARSE_NAME"`
+			Port  int    `conf:"env:PARSE_PORT,min:1"`
+			Debug bool
+		}
+
+		cfg := Config{Debug: true}
+		if err := structconf.Parse(context.Background(), &cfg); err != nil {
+			t.Fatal("failed to Parse:", err)
+		}
+
+		if cfg != (Config{Name: "app", Port: 8080, Debug: true}) {
+			t.Errorf("unexpected struct %+v", cfg)
+		}
+	})
+}
+
+// labels merges comma separated key=value pairs into the map.
+type labels map[string]string
+
+func (l *labels) UnmarshalText(text []byte) error {
+	if *l == nil {
+		*l = make(labels)
+	}
+
+	for _, pair := range strings.Split(string(text), ",") {
+		key, val, ok := strings.Cut(pair, "=")
+		if !ok {
+			return fmt.Errorf("invalid label %q", pair)
+		}
+		(*l)[key] = val
+	}
+
+	return nil
+}
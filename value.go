@@ -0,0 +1,174 @@
+package structconf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kevinfalting/structconf/stronf"
+)
+
+// Value holds an immutable snapshot of a configuration struct of type T that
+// can be shared across goroutines while it's reloaded. Each reload parses a
+// fresh copy of the template into a new snapshot and swaps it in atomically,
+// so readers never see a half-updated struct. Snapshots are shared, so any
+// slices, maps, or pointers in them must not be modified. Since every reload
+// reads the environment again, environment variables can't be unset once
+// they're consumed, see [NewValue].
+type Value[T any] struct {
+	template T
+	loader   *Loader
+
+	current atomic.Pointer[T]
+
+	mu          sync.Mutex
+	subscribers []subscriber[T]
+}
+
+type subscriber[T any] struct {
+	path string
+	fn   func(old, new T)
+}
+
+// NewValue returns a [Value] with its first snapshot parsed from a copy of
+// template, using the options on every parse. Since the flags are only parsed
+// once, [WithFlagSet] should be given a [flag.FlagSet] that has already been
+// parsed, or the flags will be parsed from os.Args on the first parse.
+//
+// Environment variables can't be unset after they're consumed, since every
+// reload reads them again, so an error is returned for [WithUnsetenv] or any
+// field with the 'unsetenv' key in the struct tag.
+func NewValue[T any](ctx context.Context, template T, optionFuncs ...optionFunc) (*Value[T], error) {
+	loader, err := NewLoader(optionFuncs...)
+	if err != nil {
+		return nil, err
+	}
+
+	if loader.opt.unsetenv {
+		return nil, errors.New("structconf: cannot unset environment variables of a reloadable value")
+	}
+
+	fields, err := stronf.SettableFieldsWithTag(&template, loader.opt.tagKey)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, field := range fields {
+		if _, ok := field.LookupTag("conf", "unsetenv"); ok {
+			return nil, fmt.Errorf("structconf: cannot unset the environment variable of field %q of a reloadable value", field.Path())
+		}
+	}
+
+	value := Value[T]{
+		template: template,
+		loader:   loader,
+	}
+
+	snapshot, err := value.parse(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	value.current.Store(snapshot)
+
+	return &value, nil
+}
+
+// Load returns a copy of the latest snapshot.
+func (v *Value[T]) Load() T {
+	return *v.current.Load()
+}
+
+// Subscribe will call fn with the old and new snapshots after every successful
+// reload. Subscribers are called in the order they subscribed, one reload at a
+// time, and must not reload the [Value].
+func (v *Value[T]) Subscribe(fn func(old, new T)) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.subscribers = append(v.subscribers, subscriber[T]{fn: fn})
+}
+
+// SubscribeField is like [Value.Subscribe], but fn is only called when the
+// value of the field at path, its full path from the root struct, changed.
+func (v *Value[T]) SubscribeField(path string, fn func(old, new T)) error {
+	fields, err := stronf.SettableFieldsWithTag(v.current.Load(), v.loader.opt.tagKey)
+	if err != nil {
+		return err
+	}
+
+	if !slices.ContainsFunc(fields, func(field stronf.Field) bool { return field.Path() == path }) {
+		return fmt.Errorf("structconf: unknown field %q", path)
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.subscribers = append(v.subscribers, subscriber[T]{path: path, fn: fn})
+
+	return nil
+}
+
+// Reload will parse a fresh copy of the template and swap it in as the latest
+// snapshot, notifying subscribers. If parsing fails, the previous snapshot is
+// kept and the error is returned.
+func (v *Value[T]) Reload(ctx context.Context) error {
+	_, err := v.reload(ctx)
+	return err
+}
+
+// reload does the work of [Value.Reload], returning the full path of every
+// field that changed.
+func (v *Value[T]) reload(ctx context.Context) ([]string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	snapshot, err := v.parse(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	changed, err := changedFields(v.current.Load(), snapshot, v.loader.opt.tagKey)
+	if err != nil {
+		return nil, err
+	}
+
+	old := v.current.Swap(snapshot)
+	for _, sub := range v.subscribers {
+		if sub.path != "" && !slices.Contains(changed, sub.path) {
+			continue
+		}
+
+		sub.fn(*old, *snapshot)
+	}
+
+	return changed, nil
+}
+
+func (v *Value[T]) parse(ctx context.Context) (*T, error) {
+	snapshot := v.template
+	if err := v.loader.Load(ctx, &snapshot); err != nil {
+		return nil, err
+	}
+
+	return &snapshot, nil
+}
+
+// changedFields returns the full path of every field whose value differs
+// between the snapshots.
+func changedFields[T any](old, new *T, tagKey string) ([]string, error) {
+	changes, err := DiffWithTag(old, new, nil, nil, tagKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for _, change := range changes {
+		changed = append(changed, change.Path)
+	}
+
+	return changed, nil
+}
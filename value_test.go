@@ -0,0 +1,136 @@
+package structconf_test
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/kevinfalting/structconf"
+)
+
+func TestValue(t *testing.T) {
+	t.Setenv("VALUE_PORT", "8080")
+
+	type Config struct {
+		Port int `conf:"env:VALUE_PORT,min:1"`
+		DB   struct {
+			Host string `conf:"env:VALUE_DB_HOST,default:localhost"`
+		}
+	}
+
+	ctx := context.Background()
+
+	value, err := structconf.NewValue(ctx, Config{})
+	if err != nil {
+		t.Fatal("failed to NewValue:", err)
+	}
+
+	if got := value.Load(); got.Port != 8080 || got.DB.Host != "localhost" {
+		t.Fatalf("unexpected first snapshot %+v", got)
+	}
+
+	var all, port, host int
+	value.Subscribe(func(old, new Config) { all++ })
+
+	if err := value.SubscribeField("Port", func(old, new Config) {
+		port++
+		if old.Port == new.Port {
+			t.Errorf("expected Port to change, got %d", new.Port)
+		}
+	}); err != nil {
+		t.Fatal("failed to SubscribeField:", err)
+	}
+
+	if err := value.SubscribeField("DB.Host", func(old, new Config) { host++ }); err != nil {
+		t.Fatal("failed to SubscribeField:", err)
+	}
+
+	t.Run("unknown field", func(t *testing.T) {
+		if err := value.SubscribeField("DB.Port", func(old, new Config) {}); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("field subscriptions", func(t *testing.T) {
+		t.Setenv("VALUE_PORT", "9090")
+
+		if err := value.Reload(ctx); err != nil {
+			t.Fatal("failed to Reload:", err)
+		}
+
+		if err := value.Reload(ctx); err != nil {
+			t.Fatal("failed to Reload:", err)
+		}
+
+		if all != 2 || port != 1 || host != 0 {
+			t.Errorf("expected 2, 1, and 0 calls, got %d, %d, and %d", all, port, host)
+		}
+	})
+
+	t.Run("failed reload keeps previous snapshot", func(t *testing.T) {
+		t.Setenv("VALUE_PORT", "0")
+
+		if err := value.Reload(ctx); err == nil {
+			t.Error("expected error, got nil")
+		}
+
+		if got := value.Load(); got.Port != 9090 {
+			t.Errorf("expected Port 9090, got %d", got.Port)
+		}
+	})
+
+	t.Run("concurrent readers", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					if got := value.Load(); got.DB.Host != "localhost" {
+						t.Errorf("unexpected snapshot %+v", got)
+						return
+					}
+				}
+			}()
+		}
+
+		for i := 0; i < 10; i++ {
+			if err := value.Reload(ctx); err != nil {
+				t.Error("failed to Reload:", err)
+			}
+		}
+
+		wg.Wait()
+	})
+}
+
+func TestNewValue_unsetenv(t *testing.T) {
+	t.Setenv("VALUE_PASS", "hunter2")
+
+	ctx := context.Background()
+
+	t.Run("tagged", func(t *testing.T) {
+		type Config struct {
+			Pass string `conf:"env:VALUE_PASS,unsetenv,required"`
+		}
+
+		if _, err := structconf.NewValue(ctx, Config{}); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	t.Run("all", func(t *testing.T) {
+		type Config struct {
+			Pass string `conf:"env:VALUE_PASS,required"`
+		}
+
+		if _, err := structconf.NewValue(ctx, Config{}, structconf.WithUnsetenv()); err == nil {
+			t.Error("expected error, got nil")
+		}
+	})
+
+	if _, ok := os.LookupEnv("VALUE_PASS"); !ok {
+		t.Error("expected VALUE_PASS to still be set")
+	}
+}
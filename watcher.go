@@ -0,0 +1,111 @@
+package structconf
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Watcher is a [Value] that also reloads when watched files change or when
+// the process receives a signal. A snapshot is only swapped in once [Parse],
+// including all of its validation, succeeds, otherwise the previous snapshot
+// is kept.
+type Watcher[T any] struct {
+	*Value[T]
+
+	mu      sync.Mutex
+	onError []func(err error)
+}
+
+// NewWatcher returns a [Watcher] with its first snapshot parsed from a copy of
+// template, as with [NewValue], including its restriction on unsetting
+// environment variables.
+func NewWatcher[T any](ctx context.Context, template T, optionFuncs ...optionFunc) (*Watcher[T], error) {
+	value, err := NewValue(ctx, template, optionFuncs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Watcher[T]{Value: value}, nil
+}
+
+// OnError will call fn with the error of every failed reload triggered by
+// [Watcher.Watch].
+func (w *Watcher[T]) OnError(fn func(err error)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.onError = append(w.onError, fn)
+}
+
+// watchStarted is called once [Watcher.Watch] has recorded the files, so that
+// tests know when it's safe to change them.
+var watchStarted = func() {}
+
+// Watch will poll the files every interval and [Value.Reload] when any of
+// them are created, removed, or modified. Failed reloads are passed to the
+// functions registered with [Watcher.OnError]. It blocks until ctx is done,
+// returning the context's error.
+func (w *Watcher[T]) Watch(ctx context.Context, interval time.Duration, files ...string) error {
+	if interval <= 0 {
+		return errors.New("structconf: watch interval must be greater than zero")
+	}
+
+	stats := statFiles(files)
+	watchStarted()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+
+		case <-ticker.C:
+			next := statFiles(files)
+			if next == stats {
+				continue
+			}
+			stats = next
+
+			if err := w.Reload(ctx); err != nil {
+				w.mu.Lock()
+				onError := w.onError
+				w.mu.Unlock()
+
+				for _, fn := range onError {
+					fn(err)
+				}
+			}
+		}
+	}
+}
+
+// statFiles returns a string that changes whenever any of the files are
+// created, removed, or modified.
+func statFiles(files []string) string {
+	var b []byte
+	for _, file := range files {
+		info, err := os.Stat(file)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			b = append(b, "missing\x00"...)
+
+		case err != nil:
+			b = append(b, "error\x00"...)
+
+		default:
+			b = info.ModTime().AppendFormat(b, time.RFC3339Nano)
+			b = append(b, ' ')
+			b = strconv.AppendInt(b, info.Size(), 10)
+			b = append(b, 0)
+		}
+	}
+
+	return string(b)
+}
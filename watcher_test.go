@@ -0,0 +1,139 @@
+package structconf_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kevinfalting/structconf"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "name")
+
+	// The file is replaced in one step, so that the poller never sees it half
+	// written.
+	writeFile := func(t *testing.T, content string) {
+		t.Helper()
+
+		tmp := filepath.Join(dir, "tmp")
+		if err := os.WriteFile(tmp, []byte(content), 0o600); err != nil {
+			t.Fatal("failed to WriteFile:", err)
+		}
+
+		if err := os.Rename(tmp, path); err != nil {
+			t.Fatal("failed to Rename:", err)
+		}
+	}
+
+	writeFile(t, "first")
+
+	t.Setenv("WATCHER_NAME", "file://"+path)
+
+	type Config struct {
+		Name    string `conf:"env:WATCHER_NAME,minlen:2"`
+		Retries int    `conf:"default:3"`
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watcher, err := structconf.NewWatcher(ctx, Config{Retries: 5}, structconf.WithReference(nil))
+	if err != nil {
+		t.Fatal("failed to NewWatcher:", err)
+	}
+
+	if got := watcher.Load(); got != (Config{Name: "first", Retries: 5}) {
+		t.Fatalf("unexpected first snapshot %+v", got)
+	}
+
+	type change struct {
+		old, new Config
+	}
+
+	// The poller and an on demand reload may both notify, which must not
+	// block while the reload holds the lock.
+	changes := make(chan change, 10)
+	watcher.Subscribe(func(old, new Config) {
+		changes <- change{old: old, new: new}
+	})
+
+	errs := make(chan error, 10)
+	watcher.OnError(func(err error) {
+		errs <- err
+	})
+
+	started := make(chan struct{})
+	defer structconf.SetWatchStarted(func() { close(started) })()
+
+	done := make(chan error)
+	go func() {
+		done <- watcher.Watch(ctx, 10*time.Millisecond, path)
+	}()
+
+	// Changing the files before they're recorded wouldn't trigger a reload.
+	select {
+	case <-started:
+	case err := <-done:
+		t.Fatal("failed to Watch:", err)
+	}
+
+	t.Run("file change reloads", func(t *testing.T) {
+		writeFile(t, "second")
+
+		select {
+		case c := <-changes:
+			if c.old.Name != "first" || c.new.Name != "second" {
+				t.Errorf("unexpected change %+v", c)
+			}
+
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for reload")
+		}
+
+		if got := watcher.Load(); got != (Config{Name: "second", Retries: 5}) {
+			t.Errorf("unexpected snapshot %+v", got)
+		}
+	})
+
+	t.Run("failed reload keeps previous snapshot", func(t *testing.T) {
+		writeFile(t, "x")
+
+		select {
+		case err := <-errs:
+			if err == nil {
+				t.Error("expected error, got nil")
+			}
+
+		case c := <-changes:
+			t.Fatalf("expected reload to fail, got %+v", c)
+
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for reload")
+		}
+
+		if got := watcher.Load(); got != (Config{Name: "second", Retries: 5}) {
+			t.Errorf("unexpected snapshot %+v", got)
+		}
+	})
+
+	t.Run("reload on demand", func(t *testing.T) {
+		writeFile(t, "on demand")
+
+		if err := watcher.Reload(ctx); err != nil {
+			t.Fatal("failed to Reload:", err)
+		}
+
+		if got := watcher.Load(); got.Name != "on demand" {
+			t.Errorf("unexpected snapshot %+v", got)
+		}
+	})
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}